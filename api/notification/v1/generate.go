@@ -0,0 +1,4 @@
+// Package notificationv1 holds the gRPC NotificationService definition and its generated code.
+package notificationv1
+
+//go:generate protoc -I ../../.. --go_out=../../.. --go_opt=paths=source_relative --go-grpc_out=../../.. --go-grpc_opt=paths=source_relative api/notification/v1/notification.proto
@@ -2,41 +2,77 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
 
 	"github.com/loganrk/worker-engine/config"
 	"github.com/loganrk/worker-engine/internal/core/port"
-	userUsecase "github.com/loganrk/worker-engine/internal/core/usecase/user"
+	notificationUsecase "github.com/loganrk/worker-engine/internal/core/usecase/notification"
 
-	emailer "github.com/loganrk/worker-engine/internal/adapters/emailer/mailjet"
+	failoverEmailer "github.com/loganrk/worker-engine/internal/adapters/emailer/failover"
+	mailjetEmailer "github.com/loganrk/worker-engine/internal/adapters/emailer/mailjet"
+	smtpEmailer "github.com/loganrk/worker-engine/internal/adapters/emailer/smtp"
+	smsSender "github.com/loganrk/worker-engine/internal/adapters/sms/twilio"
 
-	messageReceiver "github.com/loganrk/utils-go/adapters/message/kafka/consumer"
+	deadLetter "github.com/loganrk/worker-engine/internal/adapters/deadLetter/kafka"
+	boltDedupStore "github.com/loganrk/worker-engine/internal/adapters/dedupStore/bolt"
+	memoryDedupStore "github.com/loganrk/worker-engine/internal/adapters/dedupStore/memory"
+	fileWatcher "github.com/loganrk/worker-engine/internal/adapters/fileWatcher/fsnotify"
 	"github.com/loganrk/worker-engine/internal/adapters/handler"
+	"github.com/loganrk/worker-engine/internal/adapters/health"
+	"github.com/loganrk/worker-engine/internal/adapters/httpServer"
+	messageReceiver "github.com/loganrk/worker-engine/internal/adapters/messageReceiver/kafka"
+	"github.com/loganrk/worker-engine/internal/adapters/metrics"
+	grpcAPI "github.com/loganrk/worker-engine/internal/adapters/notificationAPI/grpc"
+	restAPI "github.com/loganrk/worker-engine/internal/adapters/notificationAPI/rest"
+	leakyBucketRatelimit "github.com/loganrk/worker-engine/internal/adapters/rateLimiter/leakyBucket"
+	redisRatelimit "github.com/loganrk/worker-engine/internal/adapters/rateLimiter/redis"
 	slidingWindowRatelimit "github.com/loganrk/worker-engine/internal/adapters/rateLimiter/slidingWindow"
+	memoryStatusStore "github.com/loganrk/worker-engine/internal/adapters/statusStore/memory"
+	"github.com/loganrk/worker-engine/internal/adapters/tracing"
 
 	cipher "github.com/loganrk/utils-go/adapters/cipher/aes"
 	logger "github.com/loganrk/utils-go/adapters/logger/zapLogger"
 )
 
+// defaultShutdownTimeout applies when application.shutdownTimeout is not configured.
+const defaultShutdownTimeout = 30 * time.Second
+
+// Components that must be initialized before the readiness probe passes.
+const (
+	componentConfig    = "config"
+	componentCipher    = "cipher"
+	componentTemplates = "templates"
+	componentEmailer   = "emailer"
+	componentKafka     = "kafka"
+)
+
 func main() {
 	// Load environment variables from .env file
 	godotenv.Load()
 
-	// Load config values from file
-	configPath := os.Getenv("CONFIG_FILE_PATH")
-	configName := os.Getenv("CONFIG_FILE_NAME")
-	configType := os.Getenv("CONFIG_FILE_TYPE")
+	// Dispatch subcommands, the worker runs when none is given
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "replay":
+			os.Exit(runReplay(os.Args[2:]))
+		case "render":
+			os.Exit(runRender(os.Args[2:]))
+		}
+	}
 
 	// Initialize app config
-	appConfig, err := config.StartConfig(configPath, config.File{
-		Name: configName,
-		Ext:  configType,
-	})
+	appConfig, err := loadConfig()
 	if err != nil {
 		log.Println("failed to load config:", err)
 		return
@@ -52,37 +88,378 @@ func main() {
 		return
 	}
 
-	// Initialize SMTP email sender
-	emailIns, emailRatelimitIns, err := initEmailer(appConfig.GetEmail(), cipherIns)
+	// Start the probe server early so liveness passes while dependencies initialize
+	healthIns := initHealth()
+	metricsIns, metricsHandler := initMetrics()
+	tracerIns, err := initTracer(appConfig.GetTracing(), appConfig.GetAppName())
+	if err != nil {
+		loggerIns.Errorw(context.Background(), "failed to initialize tracing", "error", err)
+		return
+	}
+	serverIns, err := initHTTPServer(appConfig.GetHTTP(), healthIns, metricsHandler, loggerIns)
+	if err != nil {
+		loggerIns.Errorw(context.Background(), "failed to start http server", "error", err)
+		return
+	}
+	healthIns.SetReady(componentConfig)
+	healthIns.SetReady(componentCipher)
+
+	// Initialize the store sharing rate limits across replicas
+	rateLimitStoreIns, err := initRateLimitStore(appConfig.GetRateLimitStore(), appConfig.GetAppName(), cipherIns, loggerIns)
+	if err != nil {
+		loggerIns.Errorw(context.Background(), "failed to initialize rate limit store", "error", err)
+		return
+	}
+
+	// Initialize channel senders and usecases
+	services, emailIns, err := initServices(appConfig, cipherIns, loggerIns, metricsIns, tracerIns, rateLimitStoreIns)
+	if err != nil {
+		loggerIns.Errorw(context.Background(), "failed to initialize services", "error", err)
+		return
+	}
+	healthIns.SetReady(componentEmailer)
+	healthIns.SetReady(componentTemplates)
+
+	// Initialize dead-letter publisher for messages that fail processing
+	deadLetterIns, err := initDeadLetter(appConfig.GetKafka(), appConfig.GetNotifications(), appConfig.GetAppName(), cipherIns)
 	if err != nil {
-		loggerIns.Errorw(context.Background(), "failed to initialize email sender", "error", err)
+		loggerIns.Errorw(context.Background(), "failed to initialize dead-letter publisher", "error", err)
 		return
 	}
 
-	// Initialize user usecase/service with logger, email sender, and user config
-	userServiceIns, err := initUserService(loggerIns, emailIns, emailRatelimitIns, appConfig.GetUser())
+	// Initialize dedup store for redelivered messages
+	dedupIns, err := initDedupStore(appConfig.GetDedup())
 	if err != nil {
-		loggerIns.Errorw(context.Background(), "failed to initialize user usecase", "error", err)
+		loggerIns.Errorw(context.Background(), "failed to initialize dedup store", "error", err)
 		return
 	}
 
-	// Register service(s) to handler
-	services := port.SvrList{User: userServiceIns}
-	handlerIns := initHandler(loggerIns, services)
+	// Register service(s) to handler, instrumented for metrics and tracing
+	handlerIns := tracerIns.InstrumentHandler(metricsIns.InstrumentHandler(
+		initHandler(loggerIns, services, deadLetterIns, dedupIns, appConfig.GetDedup().GetWindow()),
+	))
 
 	//Initialize Kafka message receiver
-	messageReceiverIns, err := initMessageReceiver(appConfig.GetKafka(), appConfig.GetAppName(), handlerIns, cipherIns)
+	messageReceiverIns, err := initMessageReceiver(appConfig.GetKafka(), appConfig.GetNotifications(), appConfig.GetAppName(), handlerIns, metricsIns, cipherIns)
 	if err != nil {
 		loggerIns.Errorw(context.Background(), "failed to initialize kafka", "error", err)
 		return
 	}
 
-	go messageReceiverIns.ListenActivationHResetTopic(context.Background(), handlerIns.ActivationError)
+	// Start the notification APIs, which feed the same handler as the Kafka consumer
+	apiServerIns, err := initAPIServer(appConfig.GetAPI(), cipherIns, loggerIns, handlerIns)
+	if err != nil {
+		loggerIns.Errorw(context.Background(), "failed to start notification api", "error", err)
+		return
+	}
+	grpcServerIns, err := initGRPCServer(appConfig.GetAPI(), cipherIns, loggerIns, handlerIns)
+	if err != nil {
+		loggerIns.Errorw(context.Background(), "failed to start grpc notification service", "error", err)
+		return
+	}
 
-	go messageReceiverIns.ListenPasswordResetTopic(context.Background(), handlerIns.PasswordResetError)
+	// Root context is cancelled on SIGINT/SIGTERM, which stops the Kafka listeners
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Kafka is ready once the consumer group is joined, and not while consuming fails.
+	// Without a consumed topic there is nothing to wait for.
+	if messageReceiverIns != nil {
+		err := messageReceiverIns.Listen(ctx, handlerIns.HandleError, func(ready bool) {
+			if ready {
+				healthIns.SetReady(componentKafka)
+			} else {
+				healthIns.SetNotReady(componentKafka)
+			}
+		})
+		if err != nil {
+			loggerIns.Errorw(ctx, "failed to start kafka consumer", "error", err)
+			return
+		}
+	} else {
+		healthIns.SetReady(componentKafka)
+	}
+
+	// Reload templates when they change on disk
+	watcherIns, err := initTemplateWatcher(ctx, appConfig.GetNotifications(), loggerIns, services.Notification)
+	if err != nil {
+		loggerIns.Errorw(ctx, "failed to watch templates", "error", err)
+	}
 
 	fmt.Println("server start")
-	select {}
+	<-ctx.Done()
+
+	shutdown(shutdownTimeout(appConfig), loggerIns, healthIns, serverIns, []port.Server{apiServerIns, grpcServerIns}, tracerIns, handlerIns, messageReceiverIns, deadLetterIns, dedupIns, watcherIns, rateLimitStoreIns, emailIns)
+	fmt.Println("server stop")
+}
+
+// shutdownTimeout returns the configured shutdown timeout, or the default when it is not set.
+func shutdownTimeout(appConfig config.App) time.Duration {
+	if timeout := appConfig.GetShutdownTimeout(); timeout > 0 {
+		return timeout
+	}
+	return defaultShutdownTimeout
+}
+
+// shutdown fails readiness, stops the notification APIs, drains in-flight messages within the
+// timeout, releases the Kafka, storage and email connections, stops the HTTP server and flushes the
+// pending spans and the logger.
+func shutdown(timeout time.Duration, loggerIns port.Logger, healthIns port.Health, serverIns port.Server, apiServers []port.Server, tracerIns port.Tracer, handlerIns port.Hanlder, messageReceiverIns port.MessageReceiver, resources ...any) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	healthIns.SetShuttingDown()
+	loggerIns.Infow(ctx, "shutting down, draining in-flight messages", "timeout", timeout)
+
+	// Stop taking API requests first, the requests being served finish within the drain below
+	for _, apiServerIns := range apiServers {
+		if apiServerIns == nil {
+			continue
+		}
+		if err := apiServerIns.Shutdown(ctx); err != nil {
+			loggerIns.Warnw(ctx, "failed to stop notification api", "error", err)
+		}
+	}
+
+	if err := handlerIns.Drain(ctx); err != nil {
+		loggerIns.Warnw(ctx, "shutdown timeout reached before in-flight messages finished", "error", err)
+	}
+
+	// Closing waits for the consume loops, which may still be stuck on a message after a timeout
+	if messageReceiverIns != nil {
+		closed := make(chan error, 1)
+		go func() {
+			closed <- messageReceiverIns.Close()
+		}()
+		select {
+		case err := <-closed:
+			if err != nil {
+				loggerIns.Warnw(ctx, "failed to close kafka consumer", "error", err)
+			}
+		case <-ctx.Done():
+			loggerIns.Warnw(ctx, "shutdown timeout reached before kafka consumer closed")
+		}
+	}
+
+	for _, resource := range resources {
+		if closer, ok := resource.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				loggerIns.Warnw(ctx, "failed to close resource", "error", err)
+			}
+		}
+	}
+
+	if serverIns != nil {
+		if err := serverIns.Shutdown(ctx); err != nil {
+			loggerIns.Warnw(ctx, "failed to stop http server", "error", err)
+		}
+	}
+
+	if err := tracerIns.Shutdown(ctx); err != nil {
+		loggerIns.Warnw(ctx, "failed to flush spans", "error", err)
+	}
+
+	loggerIns.Sync(ctx)
+}
+
+// initHealth creates the readiness tracker for the components initialized at startup.
+func initHealth() port.Health {
+	return health.New(componentConfig, componentCipher, componentTemplates, componentEmailer, componentKafka)
+}
+
+// initMetrics creates the Prometheus collectors used to instrument the pipeline, together
+// with the handler serving them.
+func initMetrics() (port.Metrics, http.Handler) {
+	metricsIns := metrics.New()
+	return metricsIns, metricsIns.Handler()
+}
+
+// initTracer installs the trace context propagator and, when enabled, the span exporter.
+func initTracer(conf config.Tracing, appName string) (port.Tracer, error) {
+	return tracing.New(tracing.Config{
+		Enabled:     conf.GetEnabled(),
+		ServiceName: appName,
+		Exporter:    conf.GetExporter(),
+		Endpoint:    conf.GetEndpoint(),
+		Insecure:    conf.GetInsecure(),
+		SampleRatio: conf.GetSampleRatio(),
+	})
+}
+
+// initHTTPServer starts the HTTP server with the probe and metrics endpoints, or returns nil when no port is configured.
+func initHTTPServer(conf config.HTTP, healthIns port.Health, metricsHandler http.Handler, loggerIns port.Logger) (port.Server, error) {
+	if conf.GetPort() == 0 {
+		return nil, nil
+	}
+
+	serverIns := httpServer.New(conf.GetPort())
+	serverIns.Handle(conf.GetHealthPath(), httpServer.Liveness())
+	serverIns.Handle(conf.GetReadyPath(), httpServer.Readiness(healthIns))
+	serverIns.Handle(conf.GetMetricsPath(), metricsHandler)
+
+	err := serverIns.Start(func(err error) {
+		loggerIns.Errorw(context.Background(), "http server stopped unexpectedly", "error", err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return serverIns, nil
+}
+
+// initAPIServer starts the notification API on its own port, or returns nil when no port is configured.
+func initAPIServer(conf config.API, cipherIns port.Cipher, loggerIns port.Logger, handlerIns port.Hanlder) (port.Server, error) {
+	if conf.GetPort() == 0 {
+		return nil, nil
+	}
+
+	// Decrypt bearer token
+	token, err := cipherIns.Decrypt(conf.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, fmt.Errorf("api token is required")
+	}
+
+	tlsConfig, err := loadAPITLS(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	apiIns := restAPI.New(restAPI.Config{
+		Token:   token,
+		Timeout: conf.GetRequestTimeout(),
+	}, loggerIns, handlerIns.Handle)
+
+	serverIns := httpServer.NewTLS(conf.GetPort(), tlsConfig)
+	serverIns.Handle(restAPI.NotificationsPath, apiIns)
+
+	err = serverIns.Start(func(err error) {
+		loggerIns.Errorw(context.Background(), "notification api stopped unexpectedly", "error", err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return serverIns, nil
+}
+
+// initGRPCServer starts the gRPC notification service on its own port, or returns nil when no port is configured.
+func initGRPCServer(conf config.API, cipherIns port.Cipher, loggerIns port.Logger, handlerIns port.Hanlder) (port.Server, error) {
+	if conf.GetGRPCPort() == 0 {
+		return nil, nil
+	}
+
+	// Decrypt bearer token
+	token, err := cipherIns.Decrypt(conf.GetToken())
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, fmt.Errorf("api token is required")
+	}
+
+	tlsConfig, err := loadAPITLS(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	serverIns := grpcAPI.New(grpcAPI.Config{
+		Port:         conf.GetGRPCPort(),
+		Token:        token,
+		Timeout:      conf.GetRequestTimeout(),
+		StatusTTL:    conf.GetStatusTTL(),
+		MaxBatchSize: conf.GetMaxBatchSize(),
+		TLS:          tlsConfig,
+	}, loggerIns, memoryStatusStore.New(), handlerIns.Handle)
+
+	err = serverIns.Start(func(err error) {
+		loggerIns.Errorw(context.Background(), "grpc notification service stopped unexpectedly", "error", err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return serverIns, nil
+}
+
+// loadAPITLS loads the certificate the notification APIs are served with, or returns nil
+// when none is configured and TLS is terminated in front of the worker.
+func loadAPITLS(conf config.API) (*tls.Config, error) {
+	if conf.GetTLSCertFile() == "" && conf.GetTLSKeyFile() == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(conf.GetTLSCertFile(), conf.GetTLSKeyFile())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load api certificate: %w", err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// loadConfig reads the app config from the location given by the environment.
+func loadConfig() (config.App, error) {
+	configPath := os.Getenv("CONFIG_FILE_PATH")
+	configName := os.Getenv("CONFIG_FILE_NAME")
+	configType := os.Getenv("CONFIG_FILE_TYPE")
+
+	return config.StartConfig(configPath, config.File{
+		Name: configName,
+		Ext:  configType,
+	})
+}
+
+// initServices initializes the channel senders and the usecases built on top of them. The
+// emailer is returned as well, its pooled connections are closed on shutdown.
+func initServices(appConfig config.App, cipherIns port.Cipher, loggerIns port.Logger, metricsIns port.Metrics, tracerIns port.Tracer, rateLimitStoreIns port.RateLimitStore) (port.SvrList, port.Emailer, error) {
+	// Initialize email sender for the configured provider(s)
+	emailIns, emailRatelimitIns, err := initEmailer(appConfig.GetEmail(), cipherIns, loggerIns, metricsIns, tracerIns, rateLimitStoreIns)
+	if err != nil {
+		return port.SvrList{}, nil, fmt.Errorf("failed to initialize email sender: %w", err)
+	}
+
+	// Initialize SMS sender, only when enabled or used by a notification type
+	var smsIns port.SMSSender
+	var smsRatelimitIns port.RateLimiter
+	if appConfig.GetSMS().GetEnabled() || usesChannel(appConfig.GetNotifications(), config.CHANNEL_SMS) {
+		smsIns, smsRatelimitIns, err = initSMSSender(appConfig.GetSMS(), cipherIns, metricsIns, tracerIns, rateLimitStoreIns)
+		if err != nil {
+			return port.SvrList{}, nil, fmt.Errorf("failed to initialize sms sender: %w", err)
+		}
+	}
+
+	// Initialize the limits of the notification types that declare one
+	typeRatelimitIns, err := initTypeRateLimiters(appConfig.GetNotifications(), metricsIns, tracerIns, rateLimitStoreIns)
+	if err != nil {
+		return port.SvrList{}, nil, fmt.Errorf("failed to initialize type rate limits: %w", err)
+	}
+	recipientRatelimitIns, err := initRecipientRateLimiters(appConfig.GetNotifications(), shutdownTimeout(appConfig), metricsIns, tracerIns, rateLimitStoreIns)
+	if err != nil {
+		return port.SvrList{}, nil, fmt.Errorf("failed to initialize recipient rate limits: %w", err)
+	}
+
+	// Initialize notification usecase/service with logger, email sender, sms sender, and the type registry
+	notificationServiceIns, err := initNotificationService(loggerIns, tracerIns, emailIns, emailRatelimitIns, smsIns, smsRatelimitIns, typeRatelimitIns, recipientRatelimitIns, appConfig.GetNotifications())
+	if err != nil {
+		return port.SvrList{}, nil, fmt.Errorf("failed to initialize notification usecase: %w", err)
+	}
+
+	return port.SvrList{Notification: notificationServiceIns}, emailIns, nil
+}
+
+// usesChannel reports whether some notification type is delivered over channel.
+func usesChannel(conf config.Notifications, channel string) bool {
+	for _, typeConf := range conf.GetTypes() {
+		if typeConf.GetChannel() == channel {
+			return true
+		}
+	}
+	return false
 }
 
 // initCipher initializes the AES cipher using the secret key from environment variable.
@@ -102,11 +479,78 @@ func initLogger(conf config.Logger) (port.Logger, error) {
 	return logger.New(loggerConf)
 }
 
-// initMessageReceiver decrypts the Kafka broker URLs and returns a Kafka receiver instance.
-func initMessageReceiver(conf config.Kafka, appName string, handlerIns port.Hanlder, cipherIns port.Cipher) (port.MessageReceiver, error) {
+// initMessageReceiver decrypts the Kafka broker URLs and returns a Kafka receiver instance
+// consuming the topic of every registered notification type, or nil when every type is
+// only sent through the notification APIs. The receiver counts the consumed messages.
+func initMessageReceiver(conf config.Kafka, notificationsConf config.Notifications, appName string, handlerIns port.Hanlder, metricsIns port.Metrics, cipherIns port.Cipher) (port.MessageReceiver, error) {
+	brokers, err := decryptBrokers(conf, cipherIns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Pass individual config values to the Kafka adapter
+	messageReceiverIns := metricsIns.InstrumentMessageReceiver(messageReceiver.New(
+		brokers,
+		strings.Replace(conf.GetConsumerGroupName(), "{{appName}}", appName, 1),
+	))
+
+	// Several types may share a topic, the handler processes any registered type
+	registered := make(map[string]bool)
+	for _, typeConf := range notificationsConf.GetTypes() {
+		topic := typeConf.GetTopic()
+		if topic == "" || registered[topic] {
+			continue
+		}
+
+		if err := messageReceiverIns.Register(topic, handlerIns.Handle); err != nil {
+			return nil, err
+		}
+		registered[topic] = true
+	}
+	if len(registered) == 0 {
+		return nil, nil
+	}
+
+	return messageReceiverIns, nil
+
+}
+
+// initDeadLetter creates the Kafka dead-letter publisher, or returns nil when dead-lettering is disabled.
+func initDeadLetter(conf config.Kafka, notificationsConf config.Notifications, appName string, cipherIns port.Cipher) (port.DeadLetterPublisher, error) {
+	if !conf.GetDeadLetterEnabled() {
+		return nil, nil
+	}
+
+	brokers, err := decryptBrokers(conf, cipherIns)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map each source topic to its dead-letter topic, types sharing a topic must agree on it.
+	// A consumed message without one could never be dead-lettered, nor committed.
+	topics := make(map[string]string)
+	for _, typeConf := range notificationsConf.GetTypes() {
+		topic, deadLetterTopic := typeConf.GetTopic(), typeConf.GetDeadLetterTopic()
+		if topic == "" {
+			continue
+		}
+		if deadLetterTopic == "" {
+			return nil, fmt.Errorf("notification type %s is consumed from %s but has no deadLetterTopic", typeConf.GetType(), topic)
+		}
+
+		if existing, ok := topics[topic]; ok && existing != deadLetterTopic {
+			return nil, fmt.Errorf("topic %s has conflicting dead-letter topics %s and %s", topic, existing, deadLetterTopic)
+		}
+		topics[topic] = deadLetterTopic
+	}
+
+	return deadLetter.New(brokers, appName, topics)
+}
+
+// decryptBrokers decrypts each configured Kafka broker address.
+func decryptBrokers(conf config.Kafka, cipherIns port.Cipher) ([]string, error) {
 	var brokers []string
 
-	// Decrypt each broker address
 	for _, brokerEnc := range conf.GetBrokers() {
 		broker, err := cipherIns.Decrypt(brokerEnc)
 		if err != nil {
@@ -115,28 +559,65 @@ func initMessageReceiver(conf config.Kafka, appName string, handlerIns port.Hanl
 		brokers = append(brokers, broker)
 	}
 
-	// Pass individual config values to the Kafka adapter
-	messageReceiverIns := messageReceiver.New(
-		brokers,
-		strings.Replace(conf.GetConsumerGroupName(), "{{appName}}", appName, 1),
-	)
+	return brokers, nil
+}
 
-	//Start message receiver consumers for different event types
-	err := messageReceiverIns.RegisterActivation(conf.GetActivationTopic(), handlerIns.ActivationPhone, handlerIns.ActivationEmail)
-	if err != nil {
-		return nil, err
+// initEmailer initializes the email sender. When more than one provider is listed they are
+// wrapped in a failover chain, each provider then applies its own rate limit inside the chain.
+func initEmailer(conf config.Email, cipherIns port.Cipher, loggerIns port.Logger, metricsIns port.Metrics, tracerIns port.Tracer, rateLimitStoreIns port.RateLimitStore) (port.Emailer, port.RateLimiter, error) {
+	providers := conf.GetProviders()
+	if len(providers) == 0 {
+		providers = []string{conf.GetProvider()}
 	}
-	err = messageReceiverIns.RegisterPasswordResetHandlers(conf.GetPasswordResetTopic(), handlerIns.PasswordResetPhone, handlerIns.PasswordResetEmail)
-	if err != nil {
-		return nil, err
+
+	if len(providers) == 1 {
+		return initEmailProvider(providers[0], conf, cipherIns, metricsIns, tracerIns, rateLimitStoreIns)
 	}
 
-	return messageReceiverIns, nil
+	chain := make([]failoverEmailer.Provider, 0, len(providers))
+	for _, name := range providers {
+		emailIns, emailRatelimitIns, err := initEmailProvider(name, conf, cipherIns, metricsIns, tracerIns, rateLimitStoreIns)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to initialize email provider %s: %w", name, err)
+		}
 
+		chain = append(chain, failoverEmailer.Provider{
+			Name:        name,
+			Emailer:     emailIns,
+			RateLimiter: emailRatelimitIns,
+		})
+	}
+
+	return failoverEmailer.New(loggerIns, chain...), nil, nil
 }
 
-// initEmailer decrypts SMTP credentials and initializes the email sender.
-func initEmailer(conf config.Email, cipherIns port.Cipher) (port.Emailer, port.RateLimiter, error) {
+// initEmailProvider initializes a single email provider by name, instrumented for metrics and tracing.
+func initEmailProvider(name string, conf config.Email, cipherIns port.Cipher, metricsIns port.Metrics, tracerIns port.Tracer, rateLimitStoreIns port.RateLimitStore) (port.Emailer, port.RateLimiter, error) {
+	var emailIns port.Emailer
+	var emailRatelimitIns port.RateLimiter
+	var err error
+
+	switch name {
+	case "", "mailjet":
+		name = "mailjet"
+		emailIns, emailRatelimitIns, err = initMailjetEmailer(conf, cipherIns, rateLimitStoreIns)
+	case "smtp":
+		emailIns, emailRatelimitIns, err = initSMTPEmailer(conf, cipherIns, rateLimitStoreIns)
+	default:
+		err = fmt.Errorf("unknown email provider: %s", name)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if emailRatelimitIns != nil {
+		emailRatelimitIns = tracerIns.InstrumentRateLimiter("email_"+name, metricsIns.InstrumentRateLimiter("email_"+name, emailRatelimitIns))
+	}
+	return tracerIns.InstrumentEmailer(name, metricsIns.InstrumentEmailer(name, emailIns)), emailRatelimitIns, nil
+}
+
+// initMailjetEmailer decrypts Mailjet credentials and initializes the email sender.
+func initMailjetEmailer(conf config.Email, cipherIns port.Cipher, rateLimitStoreIns port.RateLimitStore) (port.Emailer, port.RateLimiter, error) {
 	// Decrypt host
 	apiKey, err := cipherIns.Decrypt(conf.GetMailjetAPIKey())
 	if err != nil {
@@ -149,25 +630,269 @@ func initEmailer(conf config.Email, cipherIns port.Cipher) (port.Emailer, port.R
 		return nil, nil, err
 	}
 
-	emailIns := emailer.New(apiKey, apiSecret, conf.GetMailjetFromEmail(), conf.GetMailjetFromName())
+	emailIns := mailjetEmailer.New(apiKey, apiSecret, conf.GetMailjetFromEmail(), conf.GetMailjetFromName())
 
 	if conf.GetMailjetRateLimitEnabled() {
-		return emailIns, slidingWindowRatelimit.New(conf.GetMailjetRateLimitMaxRequest(), conf.GetMailjetRateLimitWindowSize()), nil
+		return emailIns, newRateLimiter(rateLimitStoreIns, "email_mailjet", conf.GetMailjetRateLimitMaxRequest(), conf.GetMailjetRateLimitWindowSize()), nil
+	}
+
+	// Return new email sender instance
+	return emailIns, nil, nil
+
+}
+
+// initSMTPEmailer decrypts SMTP credentials and initializes the email sender.
+func initSMTPEmailer(conf config.Email, cipherIns port.Cipher, rateLimitStoreIns port.RateLimitStore) (port.Emailer, port.RateLimiter, error) {
+	// Decrypt host
+	host, err := cipherIns.Decrypt(conf.GetSMTPHost())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Decrypt username and password, an empty username disables authentication
+	var username, password string
+	if conf.GetSMTPUsername() != "" {
+		username, err = cipherIns.Decrypt(conf.GetSMTPUsername())
+		if err != nil {
+			return nil, nil, err
+		}
+
+		password, err = cipherIns.Decrypt(conf.GetSMTPPassword())
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	emailIns := smtpEmailer.New(smtpEmailer.Config{
+		Host:       host,
+		Port:       conf.GetSMTPPort(),
+		Username:   username,
+		Password:   password,
+		From:       conf.GetSMTPFromEmail(),
+		FromName:   conf.GetSMTPFromName(),
+		TLSMode:    conf.GetSMTPTLSMode(),
+		AuthMethod: conf.GetSMTPAuthMethod(),
+		Timeout:    conf.GetSMTPTimeout(),
+	})
+
+	if conf.GetSMTPRateLimitEnabled() {
+		return emailIns, newRateLimiter(rateLimitStoreIns, "email_smtp", conf.GetSMTPRateLimitMaxRequest(), conf.GetSMTPRateLimitWindowSize()), nil
 	}
 
 	// Return new email sender instance
 	return emailIns, nil, nil
+}
+
+// initSMSSender decrypts Twilio credentials and initializes the SMS sender.
+func initSMSSender(conf config.SMS, cipherIns port.Cipher, metricsIns port.Metrics, tracerIns port.Tracer, rateLimitStoreIns port.RateLimitStore) (port.SMSSender, port.RateLimiter, error) {
+	// Decrypt account SID
+	accountSID, err := cipherIns.Decrypt(conf.GetTwilioAccountSID())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Decrypt auth token
+	authToken, err := cipherIns.Decrypt(conf.GetTwilioAuthToken())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	smsIns := smsSender.New(conf.GetTwilioBaseURL(), accountSID, authToken, conf.GetTwilioFromNumber(), conf.GetTwilioTimeout())
+	instrumentedSMSIns := tracerIns.InstrumentSMSSender("twilio", metricsIns.InstrumentSMSSender("twilio", smsIns))
+
+	if conf.GetTwilioRateLimitEnabled() {
+		smsRatelimitIns := newRateLimiter(rateLimitStoreIns, "sms_twilio", conf.GetTwilioRateLimitMaxRequest(), conf.GetTwilioRateLimitWindowSize())
+		return instrumentedSMSIns, tracerIns.InstrumentRateLimiter("sms_twilio", metricsIns.InstrumentRateLimiter("sms_twilio", smsRatelimitIns)), nil
+	}
+
+	// Return new sms sender instance
+	return instrumentedSMSIns, nil, nil
+}
+
+// initDedupStore creates the configured dedup store, or returns nil when deduplication is disabled.
+func initDedupStore(conf config.Dedup) (port.DedupStore, error) {
+	if !conf.GetEnabled() {
+		return nil, nil
+	}
 
+	switch conf.GetStore() {
+	case "", "memory":
+		return memoryDedupStore.New(), nil
+	case "bolt":
+		return boltDedupStore.New(conf.GetPath())
+	default:
+		return nil, fmt.Errorf("unknown dedup store: %s", conf.GetStore())
+	}
 }
 
-// initHandler initializes the message handler with logger and available services.
-func initHandler(logger port.Logger, services port.SvrList) port.Hanlder {
-	return handler.New(logger, services)
+// initTemplateWatcher reloads the notification templates whenever their files change, or returns nil when reloading is disabled.
+func initTemplateWatcher(ctx context.Context, conf config.Notifications, loggerIns port.Logger, notificationServiceIns port.NotificationSvr) (port.FileWatcher, error) {
+	if !conf.GetTemplateReloadEnabled() {
+		return nil, nil
+	}
+
+	watcherIns, err := fileWatcher.New(loggerIns, conf.GetTemplateReloadDebounce())
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []string{
+		conf.GetLayoutPath(),
+		conf.GetLayoutPartialsDir(),
+	}
+	for _, typeConf := range conf.GetTypes() {
+		candidates = append(candidates,
+			typeConf.GetTemplatePath(),
+			typeConf.GetTextTemplatePath(),
+			typeConf.GetSubjectTemplatePath(),
+			typeConf.GetLocaleDir(),
+		)
+	}
+
+	// Types may share files, each path is watched once
+	var paths []string
+	watched := make(map[string]bool)
+	for _, path := range candidates {
+		if path != "" && !watched[path] {
+			paths = append(paths, path)
+			watched[path] = true
+		}
+	}
+	err = watcherIns.Watch(ctx, paths, func() {
+		// Failures are logged by the usecase, which keeps serving the last good templates
+		notificationServiceIns.ReloadTemplates(ctx)
+	})
+	if err != nil {
+		watcherIns.Close()
+		return nil, err
+	}
+
+	return watcherIns, nil
+}
+
+// initHandler initializes the message handler with logger, available services, dead-letter publisher and dedup store.
+func initHandler(logger port.Logger, services port.SvrList, deadLetterIns port.DeadLetterPublisher, dedupIns port.DedupStore, dedupWindow time.Duration) port.Hanlder {
+	return handler.New(logger, services, deadLetterIns, dedupIns, dedupWindow)
+}
+
+// initRateLimitStore creates the store sharing rate limits across replicas, or returns nil when
+// every replica applies its limits on its own.
+func initRateLimitStore(conf config.RateLimitStore, appName string, cipherIns port.Cipher, loggerIns port.Logger) (port.RateLimitStore, error) {
+	if !conf.GetEnabled() {
+		return nil, nil
+	}
+
+	// Decrypt address
+	address, err := cipherIns.Decrypt(conf.GetAddress())
+	if err != nil {
+		return nil, err
+	}
+
+	// Decrypt password, an empty password disables authentication
+	var password string
+	if conf.GetPassword() != "" {
+		password, err = cipherIns.Decrypt(conf.GetPassword())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keyPrefix := conf.GetKeyPrefix()
+	if keyPrefix == "" {
+		keyPrefix = appName + ":ratelimit"
+	}
+
+	return redisRatelimit.New(redisRatelimit.Config{
+		Address:       address,
+		Password:      password,
+		DB:            conf.GetDB(),
+		KeyPrefix:     keyPrefix,
+		Timeout:       conf.GetTimeout(),
+		RetryInterval: conf.GetRetryInterval(),
+	}, loggerIns), nil
+}
+
+// newRateLimiter creates a sliding window limiter, shared across replicas when a rate limit store
+// is configured. The local limiter is then the fallback while the store is unreachable.
+func newRateLimiter(rateLimitStoreIns port.RateLimitStore, name string, maxRequests int, window time.Duration) port.RateLimiter {
+	rateLimitIns := slidingWindowRatelimit.New(maxRequests, window)
+	if rateLimitStoreIns == nil {
+		return rateLimitIns
+	}
+	return rateLimitStoreIns.SlidingWindow(name, maxRequests, window, rateLimitIns)
+}
+
+// initTypeRateLimiters creates the limiters of the notification types with a rate limit, keyed by type.
+func initTypeRateLimiters(conf config.Notifications, metricsIns port.Metrics, tracerIns port.Tracer, rateLimitStoreIns port.RateLimitStore) (map[string]port.RateLimiter, error) {
+	rateLimiters := make(map[string]port.RateLimiter)
+	for _, typeConf := range conf.GetTypes() {
+		if !typeConf.GetRateLimitEnabled() {
+			continue
+		}
+
+		if typeConf.GetRateLimitMaxRequest() <= 0 || typeConf.GetRateLimitWindowSize() <= 0 {
+			return nil, fmt.Errorf("rate limit of %s needs maxRequests and windowSize", typeConf.GetType())
+		}
+
+		name := "type_" + typeConf.GetType()
+		rateLimitIns := newRateLimiter(rateLimitStoreIns, name, typeConf.GetRateLimitMaxRequest(), typeConf.GetRateLimitWindowSize())
+		rateLimiters[typeConf.GetType()] = tracerIns.InstrumentRateLimiter(name, metricsIns.InstrumentRateLimiter(name, rateLimitIns))
+	}
+	return rateLimiters, nil
+}
+
+// initRecipientRateLimiters creates the per-recipient limiters of the notification types with a
+// recipient rate limit, keyed by type. A delayed message must be done waiting before the
+// shutdown timeout, so that it can still be sent or dead-lettered on shutdown.
+func initRecipientRateLimiters(conf config.Notifications, shutdownTimeout time.Duration, metricsIns port.Metrics, tracerIns port.Tracer, rateLimitStoreIns port.RateLimitStore) (map[string]port.KeyedRateLimiter, error) {
+	rateLimiters := make(map[string]port.KeyedRateLimiter)
+	for _, typeConf := range conf.GetTypes() {
+		if !typeConf.GetRecipientRateLimitEnabled() {
+			continue
+		}
+
+		if typeConf.GetRecipientRateLimitMaxRequest() <= 0 || typeConf.GetRecipientRateLimitWindowSize() <= 0 {
+			return nil, fmt.Errorf("recipient rate limit of %s needs maxRequests and windowSize", typeConf.GetType())
+		}
+
+		switch typeConf.GetRecipientRateLimitAction() {
+		case config.RATE_LIMIT_ACTION_DROP, config.RATE_LIMIT_ACTION_DEAD_LETTER:
+		case config.RATE_LIMIT_ACTION_DELAY:
+			if typeConf.GetRecipientRateLimitMaxDelay() > shutdownTimeout {
+				return nil, fmt.Errorf("recipient rate limit maxDelay of %s exceeds the shutdown timeout of %s", typeConf.GetType(), shutdownTimeout)
+			}
+		default:
+			return nil, fmt.Errorf("unknown recipient rate limit action of %s: %s", typeConf.GetType(), typeConf.GetRecipientRateLimitAction())
+		}
+
+		// The local limiter is the fallback of the shared one while the store is unreachable
+		name := "recipient_" + typeConf.GetType()
+		maxRequests, window := typeConf.GetRecipientRateLimitMaxRequest(), typeConf.GetRecipientRateLimitWindowSize()
+
+		var rateLimitIns port.KeyedRateLimiter
+		switch typeConf.GetRecipientRateLimitAlgorithm() {
+		case config.RATE_LIMIT_SLIDING_WINDOW:
+			rateLimitIns = slidingWindowRatelimit.NewKeyed(maxRequests, window)
+			if rateLimitStoreIns != nil {
+				rateLimitIns = rateLimitStoreIns.KeyedSlidingWindow(name, maxRequests, window, rateLimitIns)
+			}
+		case config.RATE_LIMIT_LEAKY_BUCKET:
+			rateLimitIns = leakyBucketRatelimit.NewKeyed(maxRequests, window)
+			if rateLimitStoreIns != nil {
+				rateLimitIns = rateLimitStoreIns.KeyedLeakyBucket(name, maxRequests, window, rateLimitIns)
+			}
+		default:
+			return nil, fmt.Errorf("unknown recipient rate limit algorithm of %s: %s", typeConf.GetType(), typeConf.GetRecipientRateLimitAlgorithm())
+		}
+
+		rateLimiters[typeConf.GetType()] = tracerIns.InstrumentKeyedRateLimiter(name, metricsIns.InstrumentKeyedRateLimiter(name, rateLimitIns))
+	}
+	return rateLimiters, nil
 }
 
-// initUserService creates a new instance of the user service/usecase.
-func initUserService(logger port.Logger, emailer port.Emailer, emailRatelimitIns port.RateLimiter, conf config.User) (port.UserSvr, error) {
+// initNotificationService creates a new instance of the notification service/usecase.
+func initNotificationService(logger port.Logger, tracerIns port.Tracer, emailer port.Emailer, emailRatelimitIns port.RateLimiter, smsSenderIns port.SMSSender, smsRatelimitIns port.RateLimiter, typeRatelimitIns map[string]port.RateLimiter, recipientRatelimitIns map[string]port.KeyedRateLimiter, conf config.Notifications) (port.NotificationSvr, error) {
 
-	// Create and return the user service
-	return userUsecase.New(conf, logger, emailer, emailRatelimitIns)
+	// Create and return the notification service
+	return notificationUsecase.New(conf, logger, tracerIns, emailer, emailRatelimitIns, smsSenderIns, smsRatelimitIns, typeRatelimitIns, recipientRatelimitIns)
 }
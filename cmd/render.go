@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+
+	"github.com/loganrk/worker-engine/internal/adapters/httpServer"
+	"github.com/loganrk/worker-engine/internal/adapters/tracing"
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// renderedNotification is what the senders would have received for a message.
+type renderedNotification struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// renderCapture stands in for the email and SMS senders, so that a message goes through the
+// regular handler and usecase path and the rendered notification is kept instead of sent.
+type renderCapture struct {
+	mu       sync.Mutex
+	rendered renderedNotification
+}
+
+func (c *renderCapture) SendEmail(ctx context.Context, to, subject, htmlBody, textBody string) error {
+	c.rendered = renderedNotification{Subject: subject, HTML: htmlBody, Text: textBody}
+	return nil
+}
+
+func (c *renderCapture) SendSMS(ctx context.Context, to, message string) error {
+	c.rendered = renderedNotification{Text: message}
+	return nil
+}
+
+// render runs msg through the handler and returns what was captured. Calls are serialized
+// since the capture holds a single notification.
+func (c *renderCapture) render(ctx context.Context, handlerIns port.Hanlder, msg port.Message) (renderedNotification, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.rendered = renderedNotification{}
+	if err := handlerIns.Handle(ctx, msg); err != nil {
+		return renderedNotification{}, err
+	}
+	return c.rendered, nil
+}
+
+// runRender implements the render subcommand, which renders a notification with the configured
+// templates without sending it and returns the process exit code.
+// Usage: worker-engine render -type <type> -macros <macros.json> [-locale <locale>] [-subject <subject>] [-out <dir>] [-serve <port>] [-host <host>]
+func runRender(args []string) int {
+	flags := flag.NewFlagSet("render", flag.ExitOnError)
+	msgType := flags.String("type", "", "notification type, e.g. verification-email")
+	locale := flags.String("locale", "", "recipient locale, e.g. pt-BR")
+	macrosFile := flags.String("macros", "", "JSON file with the macros, e.g. {\"name\": \"Ana\"}")
+	subject := flags.String("subject", "", "subject override, as sent by a producer")
+	out := flags.String("out", "", "directory to write subject.txt, body.html and body.txt to instead of stdout")
+	serve := flags.Int("serve", 0, "serve a preview page on this port, re-rendered when the templates change")
+	host := flags.String("host", "127.0.0.1", "interface the preview page listens on, the rendered sample data is exposed to whoever can reach it")
+	flags.Parse(args)
+
+	if *msgType == "" || *macrosFile == "" {
+		log.Println("-type and -macros are required")
+		flags.Usage()
+		return 2
+	}
+
+	appConfig, err := loadConfig()
+	if err != nil {
+		log.Println("failed to load config:", err)
+		return 1
+	}
+
+	loggerIns, err := initLogger(appConfig.GetLogger())
+	if err != nil {
+		log.Println("failed to initialize logger:", err)
+		return 1
+	}
+	defer loggerIns.Sync(context.Background())
+
+	// Previews are not traced, the tracer only propagates
+	tracerIns, err := tracing.New(tracing.Config{ServiceName: appConfig.GetAppName()})
+	if err != nil {
+		log.Println("failed to initialize tracing:", err)
+		return 1
+	}
+
+	// The usecase loads and validates the templates exactly as the worker does
+	captureIns := &renderCapture{}
+	notificationServiceIns, err := initNotificationService(loggerIns, tracerIns, captureIns, nil, captureIns, nil, nil, nil, appConfig.GetNotifications())
+	if err != nil {
+		log.Println("failed to load templates:", err)
+		return 1
+	}
+	handlerIns := initHandler(loggerIns, port.SvrList{Notification: notificationServiceIns}, nil, nil, 0)
+
+	// The macros file is re-read on every render so that the preview follows its changes too
+	renderFn := func(ctx context.Context) (renderedNotification, error) {
+		macros, err := readMacros(*macrosFile)
+		if err != nil {
+			return renderedNotification{}, err
+		}
+
+		return captureIns.render(ctx, handlerIns, port.Message{
+			Type:    *msgType,
+			To:      "preview",
+			Subject: *subject,
+			Locale:  *locale,
+			Macros:  macros,
+		})
+	}
+
+	if *serve != 0 {
+		return servePreview(*host, *serve, notificationServiceIns, renderFn)
+	}
+
+	rendered, err := renderFn(context.Background())
+	if err != nil {
+		log.Println("failed to render:", err)
+		return 1
+	}
+
+	if *out != "" {
+		if err := writeRendered(*out, rendered); err != nil {
+			log.Println("failed to write output:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if rendered.Subject != "" {
+		fmt.Printf("Subject: %s\n\n", rendered.Subject)
+	}
+	if rendered.HTML != "" {
+		fmt.Printf("--- text ---\n%s\n--- html ---\n%s\n", rendered.Text, rendered.HTML)
+	} else {
+		fmt.Println(rendered.Text)
+	}
+	return 0
+}
+
+func readMacros(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var macros map[string]string
+	if err := json.Unmarshal(data, &macros); err != nil {
+		return nil, fmt.Errorf("invalid macros file %s: %w", path, err)
+	}
+	return macros, nil
+}
+
+// writeRendered writes the non-empty parts of the notification into dir.
+func writeRendered(dir string, rendered renderedNotification) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	for _, file := range []struct{ name, content string }{
+		{"subject.txt", rendered.Subject},
+		{"body.html", rendered.HTML},
+		{"body.txt", rendered.Text},
+	} {
+		if file.content == "" {
+			continue
+		}
+		path := filepath.Join(dir, file.name)
+		if err := os.WriteFile(path, []byte(file.content), 0o644); err != nil {
+			return err
+		}
+		fmt.Println("wrote", path)
+	}
+	return nil
+}
+
+// previewPage shows the rendered notification and reloads it whenever /version changes.
+var previewPage = template.Must(template.New("preview").Parse(`<!DOCTYPE html>
+<html>
+<head>
+    <meta charset="UTF-8">
+    <title>Preview</title>
+    <style>
+        body { font-family: sans-serif; margin: 0; display: flex; flex-direction: column; height: 100vh; }
+        header { padding: 8px 16px; background: #222; color: #fff; }
+        main { flex: 1; display: flex; min-height: 0; }
+        iframe { flex: 2; border: 0; border-right: 1px solid #ccc; }
+        pre { flex: 1; margin: 0; padding: 16px; overflow: auto; white-space: pre-wrap; }
+        .error { color: #b00; padding: 16px; }
+    </style>
+</head>
+<body>
+    {{if .Error}}
+    <header>Render failed</header>
+    <pre class="error">{{.Error}}</pre>
+    {{else}}
+    <header>{{if .Subject}}Subject: {{.Subject}}{{else}}SMS{{end}}</header>
+    <main>
+        {{if .HTML}}<iframe src="/html"></iframe>{{end}}
+        <pre>{{.Text}}</pre>
+    </main>
+    {{end}}
+    <script>
+        const version = "{{.Version}}";
+        setInterval(async () => {
+            const res = await fetch("/version");
+            if (res.ok && (await res.text()) !== version) location.reload();
+        }, 1000);
+    </script>
+</body>
+</html>
+`))
+
+// servePreview serves the preview page until interrupted. Every request reloads the templates
+// from disk and renders again.
+func servePreview(listenHost string, listenPort int, notificationServiceIns port.NotificationSvr, renderFn func(context.Context) (renderedNotification, error)) int {
+	renderLatest := func(r *http.Request) (renderedNotification, string, error) {
+		if err := notificationServiceIns.ReloadTemplates(r.Context()); err != nil {
+			return renderedNotification{}, "", err
+		}
+
+		rendered, err := renderFn(r.Context())
+		if err != nil {
+			return renderedNotification{}, "", err
+		}
+
+		digest := sha256.Sum256([]byte(rendered.Subject + "\x00" + rendered.HTML + "\x00" + rendered.Text))
+		return rendered, hex.EncodeToString(digest[:]), nil
+	}
+
+	serverIns := httpServer.NewHost(listenHost, listenPort)
+	serverIns.Handle("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rendered, version, err := renderLatest(r)
+
+		data := struct {
+			renderedNotification
+			Version string
+			Error   string
+		}{renderedNotification: rendered, Version: version}
+		if err != nil {
+			data.Error = err.Error()
+			// Keep polling so the page recovers once the template is fixed
+			data.Version = "error:" + data.Error
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		previewPage.Execute(w, data)
+	}))
+	serverIns.Handle("/html", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rendered, _, err := renderLatest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(rendered.HTML))
+	}))
+	serverIns.Handle("/version", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, version, err := renderLatest(r)
+		if err != nil {
+			version = "error:" + err.Error()
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(version))
+	}))
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := serverIns.Start(func(err error) {
+		log.Println("preview server stopped:", err)
+		stop()
+	})
+	if err != nil {
+		log.Println("failed to start preview server:", err)
+		return 1
+	}
+
+	fmt.Printf("preview at http://%s/\n", net.JoinHostPort(listenHost, strconv.Itoa(listenPort)))
+	<-ctx.Done()
+
+	if err := serverIns.Shutdown(context.Background()); err != nil {
+		log.Println("failed to stop preview server:", err)
+		return 1
+	}
+	return 0
+}
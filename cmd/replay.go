@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/loganrk/worker-engine/config"
+	"github.com/loganrk/worker-engine/internal/core/port"
+
+	deadLetterFile "github.com/loganrk/worker-engine/internal/adapters/deadLetter/file"
+	deadLetter "github.com/loganrk/worker-engine/internal/adapters/deadLetter/kafka"
+	messageReceiver "github.com/loganrk/worker-engine/internal/adapters/messageReceiver/kafka"
+)
+
+// replayFilter selects which dead letters are resent.
+type replayFilter struct {
+	msgType string
+	to      string
+	since   time.Time
+	until   time.Time
+}
+
+// runReplay implements the replay subcommand, which pushes dead-lettered messages back
+// through the handler and returns the process exit code. Usage: worker-engine replay (-topic <dlq> | -file <export.jsonl>) [filters] [-dry-run]
+func runReplay(args []string) int {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	topic := flags.String("topic", "", "dead-letter topic to read from")
+	file := flags.String("file", "", "JSONL file of exported dead letters to read from")
+	msgType := flags.String("type", "", "only replay messages of this type, e.g. verification-email")
+	to := flags.String("to", "", "only replay messages sent to this recipient")
+	since := flags.String("since", "", "only replay messages that failed at or after this RFC3339 time")
+	until := flags.String("until", "", "only replay messages that failed before this RFC3339 time")
+	dryRun := flags.Bool("dry-run", false, "print the messages that would be resent without sending them")
+	flags.Parse(args)
+
+	if (*topic == "") == (*file == "") {
+		log.Println("exactly one of -topic or -file is required")
+		flags.Usage()
+		return 2
+	}
+
+	filter := replayFilter{msgType: *msgType, to: *to}
+	var err error
+	if filter.since, err = parseReplayTime(*since); err != nil {
+		log.Println("invalid -since:", err)
+		return 2
+	}
+	if filter.until, err = parseReplayTime(*until); err != nil {
+		log.Println("invalid -until:", err)
+		return 2
+	}
+
+	appConfig, err := loadConfig()
+	if err != nil {
+		log.Println("failed to load config:", err)
+		return 1
+	}
+
+	cipherIns := initCipher()
+
+	loggerIns, err := initLogger(appConfig.GetLogger())
+	if err != nil {
+		log.Println("failed to initialize logger:", err)
+		return 1
+	}
+	defer loggerIns.Sync(context.Background())
+
+	readerIns, closeReader, err := initDeadLetterReader(appConfig.GetKafka(), appConfig.GetAppName(), cipherIns, *topic, *file)
+	if err != nil {
+		log.Println("failed to initialize dead-letter reader:", err)
+		return 1
+	}
+	defer closeReader()
+
+	// Senders are only needed when messages are actually resent
+	var handlerIns port.Hanlder
+	if !*dryRun {
+		tracerIns, err := initTracer(appConfig.GetTracing(), appConfig.GetAppName())
+		if err != nil {
+			log.Println("failed to initialize tracing:", err)
+			return 1
+		}
+		defer tracerIns.Shutdown(context.Background())
+
+		// Resent messages count against the limits shared with the running workers
+		rateLimitStoreIns, err := initRateLimitStore(appConfig.GetRateLimitStore(), appConfig.GetAppName(), cipherIns, loggerIns)
+		if err != nil {
+			log.Println("failed to initialize rate limit store:", err)
+			return 1
+		}
+		if rateLimitStoreIns != nil {
+			defer rateLimitStoreIns.Close()
+		}
+
+		// Replay serves no metrics endpoint, the collectors only satisfy the instrumentation
+		metricsIns, _ := initMetrics()
+		services, emailIns, err := initServices(appConfig, cipherIns, loggerIns, metricsIns, tracerIns, rateLimitStoreIns)
+		if err != nil {
+			log.Println("failed to initialize services:", err)
+			return 1
+		}
+		if closer, ok := emailIns.(io.Closer); ok {
+			defer closer.Close()
+		}
+		// Replayed messages are marked as processed, so that replaying the same dead letters
+		// again skips the ones already resent. The bolt store is locked by a running worker.
+		dedupIns, err := initDedupStore(appConfig.GetDedup())
+		if err != nil {
+			log.Println("failed to initialize dedup store:", err)
+			return 1
+		}
+		if closer, ok := dedupIns.(io.Closer); ok {
+			defer closer.Close()
+		}
+		handlerIns = tracerIns.InstrumentHandler(initHandler(loggerIns, services, nil, dedupIns, appConfig.GetDedup().GetWindow()))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var matched, replayed, failed int
+	err = readerIns.ReadDeadLetters(ctx, func(record port.DeadLetter) error {
+		msg, err := messageReceiver.DecodeMessage([]byte(record.Payload))
+		if err != nil {
+			fmt.Printf("skip   %s/%d/%d: unreadable payload: %v\n", record.SourceTopic, record.Partition, record.Offset, err)
+			return nil
+		}
+
+		if !filter.match(record, msg) {
+			return nil
+		}
+		matched++
+
+		if *dryRun {
+			fmt.Printf("resend %s/%d/%d: type=%s to=%s failedAt=%s error=%q\n", record.SourceTopic, record.Partition, record.Offset, msg.Type, msg.To, record.FailedAt.Format(time.RFC3339), record.Error)
+			return nil
+		}
+
+		if err := handlerIns.Handle(ctx, msg); err != nil {
+			failed++
+			fmt.Printf("failed %s/%d/%d: type=%s to=%s error=%v\n", record.SourceTopic, record.Partition, record.Offset, msg.Type, msg.To, err)
+			return nil
+		}
+
+		replayed++
+		fmt.Printf("sent   %s/%d/%d: type=%s to=%s\n", record.SourceTopic, record.Partition, record.Offset, msg.Type, msg.To)
+		return nil
+	})
+
+	fmt.Printf("matched=%d replayed=%d failed=%d dryRun=%t\n", matched, replayed, failed, *dryRun)
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Println("failed to read dead letters:", err)
+		return 1
+	}
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// initDeadLetterReader returns the reader for the selected source together with its cleanup function.
+func initDeadLetterReader(conf config.Kafka, appName string, cipherIns port.Cipher, topic, file string) (port.DeadLetterReader, func(), error) {
+	if file != "" {
+		return deadLetterFile.NewReader(file), func() {}, nil
+	}
+
+	brokers, err := decryptBrokers(conf, cipherIns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	readerIns, err := deadLetter.NewReader(brokers, appName+"-replay", topic)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return readerIns, func() { readerIns.Close() }, nil
+}
+
+func (f replayFilter) match(record port.DeadLetter, msg port.Message) bool {
+	if f.msgType != "" && msg.Type != f.msgType {
+		return false
+	}
+	if f.to != "" && msg.To != f.to {
+		return false
+	}
+	if !f.since.IsZero() && record.FailedAt.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !record.FailedAt.Before(f.until) {
+		return false
+	}
+	return true
+}
+
+func parseReplayTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
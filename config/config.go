@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -12,10 +13,17 @@ type File struct {
 }
 type App interface {
 	GetAppName() string
+	GetShutdownTimeout() time.Duration
 	GetLogger() Logger
 	GetKafka() Kafka
-	GetUser() User
+	GetNotifications() Notifications
 	GetEmail() Email
+	GetSMS() SMS
+	GetDedup() Dedup
+	GetHTTP() HTTP
+	GetTracing() Tracing
+	GetAPI() API
+	GetRateLimitStore() RateLimitStore
 }
 
 func StartConfig(path string, file File) (App, error) {
@@ -44,12 +52,16 @@ func (a app) GetAppName() string {
 	return a.Application.Name
 }
 
+func (a app) GetShutdownTimeout() time.Duration {
+	return a.Application.ShutdownTimeout
+}
+
 func (a app) GetLogger() Logger {
 	return a.Logger
 }
 
-func (a app) GetUser() User {
-	return a.User
+func (a app) GetNotifications() Notifications {
+	return a.Notifications
 }
 
 func (a app) GetKafka() Kafka {
@@ -58,3 +70,27 @@ func (a app) GetKafka() Kafka {
 func (a app) GetEmail() Email {
 	return a.Email
 }
+
+func (a app) GetSMS() SMS {
+	return a.SMS
+}
+
+func (a app) GetDedup() Dedup {
+	return a.Dedup
+}
+
+func (a app) GetHTTP() HTTP {
+	return a.HTTP
+}
+
+func (a app) GetTracing() Tracing {
+	return a.Tracing
+}
+
+func (a app) GetAPI() API {
+	return a.API
+}
+
+func (a app) GetRateLimitStore() RateLimitStore {
+	return a.RateLimitStore
+}
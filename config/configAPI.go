@@ -0,0 +1,52 @@
+package config
+
+import "time"
+
+type API interface {
+	GetPort() int
+	GetGRPCPort() int
+	GetToken() string
+	GetRequestTimeout() time.Duration
+	GetMaxBatchSize() int
+	GetStatusTTL() time.Duration
+	GetTLSCertFile() string
+	GetTLSKeyFile() string
+}
+
+func (a api) GetPort() int {
+	return a.Port
+}
+
+func (a api) GetGRPCPort() int {
+	return a.GRPCPort
+}
+
+func (a api) GetToken() string {
+	return a.Token
+}
+
+func (a api) GetRequestTimeout() time.Duration {
+	if a.RequestTimeout <= 0 {
+		return 30 * time.Second
+	}
+	return a.RequestTimeout
+}
+
+func (a api) GetMaxBatchSize() int {
+	return a.MaxBatchSize
+}
+
+func (a api) GetStatusTTL() time.Duration {
+	if a.StatusTTL <= 0 {
+		return 24 * time.Hour
+	}
+	return a.StatusTTL
+}
+
+func (a api) GetTLSCertFile() string {
+	return a.TLS.CertFile
+}
+
+func (a api) GetTLSKeyFile() string {
+	return a.TLS.KeyFile
+}
@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+type Dedup interface {
+	GetEnabled() bool
+	GetStore() string
+	GetPath() string
+	GetWindow() time.Duration
+}
+
+func (d dedup) GetEnabled() bool {
+	return d.Enabled
+}
+
+func (d dedup) GetStore() string {
+	return d.Store
+}
+
+func (d dedup) GetPath() string {
+	return d.Path
+}
+
+// GetWindow defaults to a day, a zero window would expire every key immediately.
+func (d dedup) GetWindow() time.Duration {
+	if d.Window <= 0 {
+		return 24 * time.Hour
+	}
+	return d.Window
+}
@@ -3,6 +3,9 @@ package config
 import "time"
 
 type Email interface {
+	GetProvider() string
+	GetProviders() []string
+
 	GetMailjetAPIKey() string
 	GetMailjetAPISecret() string
 	GetMailjetFromEmail() string
@@ -10,6 +13,27 @@ type Email interface {
 	GetMailjetRateLimitEnabled() bool
 	GetMailjetRateLimitMaxRequest() int
 	GetMailjetRateLimitWindowSize() time.Duration
+
+	GetSMTPHost() string
+	GetSMTPPort() int
+	GetSMTPUsername() string
+	GetSMTPPassword() string
+	GetSMTPFromEmail() string
+	GetSMTPFromName() string
+	GetSMTPTLSMode() string
+	GetSMTPAuthMethod() string
+	GetSMTPTimeout() time.Duration
+	GetSMTPRateLimitEnabled() bool
+	GetSMTPRateLimitMaxRequest() int
+	GetSMTPRateLimitWindowSize() time.Duration
+}
+
+func (e email) GetProvider() string {
+	return e.Provider
+}
+
+func (e email) GetProviders() []string {
+	return e.Providers
 }
 
 func (e email) GetMailjetAPIKey() string {
@@ -37,3 +61,51 @@ func (e email) GetMailjetRateLimitMaxRequest() int {
 func (e email) GetMailjetRateLimitWindowSize() time.Duration {
 	return e.Mailjet.RateLimit.WindowSize
 }
+
+func (e email) GetSMTPHost() string {
+	return e.SMTP.Host
+}
+
+func (e email) GetSMTPPort() int {
+	return e.SMTP.Port
+}
+
+func (e email) GetSMTPUsername() string {
+	return e.SMTP.Username
+}
+
+func (e email) GetSMTPPassword() string {
+	return e.SMTP.Password
+}
+
+func (e email) GetSMTPFromEmail() string {
+	return e.SMTP.FromEmail
+}
+
+func (e email) GetSMTPFromName() string {
+	return e.SMTP.FromName
+}
+
+func (e email) GetSMTPTLSMode() string {
+	return e.SMTP.TLSMode
+}
+
+func (e email) GetSMTPAuthMethod() string {
+	return e.SMTP.AuthMethod
+}
+
+func (e email) GetSMTPTimeout() time.Duration {
+	return e.SMTP.Timeout
+}
+
+func (e email) GetSMTPRateLimitEnabled() bool {
+	return e.SMTP.RateLimit.Enabled
+}
+
+func (e email) GetSMTPRateLimitMaxRequest() int {
+	return e.SMTP.RateLimit.MaxRequests
+}
+
+func (e email) GetSMTPRateLimitWindowSize() time.Duration {
+	return e.SMTP.RateLimit.WindowSize
+}
@@ -0,0 +1,33 @@
+package config
+
+type HTTP interface {
+	GetPort() int
+	GetHealthPath() string
+	GetReadyPath() string
+	GetMetricsPath() string
+}
+
+func (h http) GetPort() int {
+	return h.Port
+}
+
+func (h http) GetHealthPath() string {
+	if h.HealthPath == "" {
+		return "/healthz"
+	}
+	return h.HealthPath
+}
+
+func (h http) GetReadyPath() string {
+	if h.ReadyPath == "" {
+		return "/readyz"
+	}
+	return h.ReadyPath
+}
+
+func (h http) GetMetricsPath() string {
+	if h.MetricsPath == "" {
+		return "/metrics"
+	}
+	return h.MetricsPath
+}
@@ -2,23 +2,18 @@ package config
 
 type Kafka interface {
 	GetBrokers() []string
-	GetActivationTopic() string
-	GetPasswordResetTopic() string
 	GetConsumerGroupName() string
+	GetDeadLetterEnabled() bool
 }
 
 func (k kafka) GetBrokers() []string {
 	return k.Brokers
 }
 
-func (k kafka) GetActivationTopic() string {
-	return k.Topics.Activation
-}
-
-func (k kafka) GetPasswordResetTopic() string {
-	return k.Topics.PasswordReset
-}
-
 func (k kafka) GetConsumerGroupName() string {
 	return k.ConsumerGroupName
 }
+
+func (k kafka) GetDeadLetterEnabled() bool {
+	return k.DeadLetter.Enabled
+}
@@ -0,0 +1,190 @@
+package config
+
+import "time"
+
+// Channels a notification type is delivered over.
+const (
+	CHANNEL_EMAIL = "email"
+	CHANNEL_SMS   = "sms"
+)
+
+// Algorithms of the recipient rate limit.
+const (
+	RATE_LIMIT_SLIDING_WINDOW = "slidingWindow"
+	RATE_LIMIT_LEAKY_BUCKET   = "leakyBucket"
+)
+
+// Actions taken for a message over the recipient rate limit.
+const (
+	RATE_LIMIT_ACTION_DROP        = "drop"       // discard the message
+	RATE_LIMIT_ACTION_DELAY       = "delay"      // wait until the recipient is under the limit again, up to a max delay
+	RATE_LIMIT_ACTION_DEAD_LETTER = "deadLetter" // publish the message to the dead-letter topic
+)
+
+type Notifications interface {
+	GetTypes() []NotificationType
+	GetInlineCSS() bool
+	GetLayoutPath() string
+	GetLayoutPartialsDir() string
+	GetRetryMaxAttempts() int
+	GetRetryBaseDelay() time.Duration
+	GetRetryMaxDelay() time.Duration
+	GetRetryJitter() float64
+	GetTemplateReloadEnabled() bool
+	GetTemplateReloadDebounce() time.Duration
+}
+
+type NotificationType interface {
+	GetType() string
+	GetTopic() string
+	GetDeadLetterTopic() string
+	GetChannel() string
+	GetRequiredMacros() []string
+	GetTemplatePath() string
+	GetTextTemplatePath() string
+	GetSubjectTemplatePath() string
+	GetLocaleDir() string
+	GetInlineCSS() bool
+	GetRateLimitEnabled() bool
+	GetRateLimitMaxRequest() int
+	GetRateLimitWindowSize() time.Duration
+	GetRecipientRateLimitEnabled() bool
+	GetRecipientRateLimitAlgorithm() string
+	GetRecipientRateLimitMaxRequest() int
+	GetRecipientRateLimitWindowSize() time.Duration
+	GetRecipientRateLimitAction() string
+	GetRecipientRateLimitMaxDelay() time.Duration
+}
+
+func (n notifications) GetTypes() []NotificationType {
+	types := make([]NotificationType, len(n.Types))
+	for i, t := range n.Types {
+		types[i] = t
+	}
+	return types
+}
+
+func (n notifications) GetInlineCSS() bool {
+	return n.InlineCSS
+}
+
+func (n notifications) GetLayoutPath() string {
+	return n.Layout.Path
+}
+
+func (n notifications) GetLayoutPartialsDir() string {
+	return n.Layout.PartialsDir
+}
+
+func (n notifications) GetRetryMaxAttempts() int {
+	return n.Retry.MaxAttempts
+}
+
+func (n notifications) GetRetryBaseDelay() time.Duration {
+	return n.Retry.BaseDelay
+}
+
+func (n notifications) GetRetryMaxDelay() time.Duration {
+	return n.Retry.MaxDelay
+}
+
+func (n notifications) GetRetryJitter() float64 {
+	return n.Retry.Jitter
+}
+
+func (n notifications) GetTemplateReloadEnabled() bool {
+	return n.TemplateReload.Enabled
+}
+
+func (n notifications) GetTemplateReloadDebounce() time.Duration {
+	return n.TemplateReload.Debounce
+}
+
+func (t notificationType) GetType() string {
+	return t.Type
+}
+
+func (t notificationType) GetTopic() string {
+	return t.Topic
+}
+
+func (t notificationType) GetDeadLetterTopic() string {
+	return t.DeadLetterTopic
+}
+
+func (t notificationType) GetChannel() string {
+	return t.Channel
+}
+
+func (t notificationType) GetRequiredMacros() []string {
+	return t.RequiredMacros
+}
+
+func (t notificationType) GetTemplatePath() string {
+	return t.Templates.Body
+}
+
+func (t notificationType) GetTextTemplatePath() string {
+	return t.Templates.Text
+}
+
+func (t notificationType) GetSubjectTemplatePath() string {
+	return t.Templates.Subject
+}
+
+func (t notificationType) GetLocaleDir() string {
+	return t.Templates.LocaleDir
+}
+
+func (t notificationType) GetInlineCSS() bool {
+	return t.InlineCSS
+}
+
+func (t notificationType) GetRateLimitEnabled() bool {
+	return t.RateLimit.Enabled
+}
+
+func (t notificationType) GetRateLimitMaxRequest() int {
+	return t.RateLimit.MaxRequests
+}
+
+func (t notificationType) GetRateLimitWindowSize() time.Duration {
+	return t.RateLimit.WindowSize
+}
+
+func (t notificationType) GetRecipientRateLimitEnabled() bool {
+	return t.RecipientRateLimit.Enabled
+}
+
+// GetRecipientRateLimitAlgorithm defaults to the sliding window.
+func (t notificationType) GetRecipientRateLimitAlgorithm() string {
+	if t.RecipientRateLimit.Algorithm == "" {
+		return RATE_LIMIT_SLIDING_WINDOW
+	}
+	return t.RecipientRateLimit.Algorithm
+}
+
+func (t notificationType) GetRecipientRateLimitMaxRequest() int {
+	return t.RecipientRateLimit.MaxRequests
+}
+
+func (t notificationType) GetRecipientRateLimitWindowSize() time.Duration {
+	return t.RecipientRateLimit.WindowSize
+}
+
+// GetRecipientRateLimitAction defaults to dropping the message.
+func (t notificationType) GetRecipientRateLimitAction() string {
+	if t.RecipientRateLimit.Action == "" {
+		return RATE_LIMIT_ACTION_DROP
+	}
+	return t.RecipientRateLimit.Action
+}
+
+// GetRecipientRateLimitMaxDelay defaults to ten seconds, a delayed message holds up the
+// other messages of its partition.
+func (t notificationType) GetRecipientRateLimitMaxDelay() time.Duration {
+	if t.RecipientRateLimit.MaxDelay <= 0 {
+		return 10 * time.Second
+	}
+	return t.RecipientRateLimit.MaxDelay
+}
@@ -0,0 +1,47 @@
+package config
+
+import "time"
+
+type RateLimitStore interface {
+	GetEnabled() bool
+	GetAddress() string
+	GetPassword() string
+	GetDB() int
+	GetKeyPrefix() string
+	GetTimeout() time.Duration
+	GetRetryInterval() time.Duration
+}
+
+func (r rateLimitStore) GetEnabled() bool {
+	return r.Enabled
+}
+
+func (r rateLimitStore) GetAddress() string {
+	return r.Address
+}
+
+func (r rateLimitStore) GetPassword() string {
+	return r.Password
+}
+
+func (r rateLimitStore) GetDB() int {
+	return r.DB
+}
+
+func (r rateLimitStore) GetKeyPrefix() string {
+	return r.KeyPrefix
+}
+
+func (r rateLimitStore) GetTimeout() time.Duration {
+	if r.Timeout <= 0 {
+		return 100 * time.Millisecond
+	}
+	return r.Timeout
+}
+
+func (r rateLimitStore) GetRetryInterval() time.Duration {
+	if r.RetryInterval <= 0 {
+		return 10 * time.Second
+	}
+	return r.RetryInterval
+}
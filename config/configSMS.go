@@ -0,0 +1,51 @@
+package config
+
+import "time"
+
+type SMS interface {
+	GetEnabled() bool
+	GetTwilioBaseURL() string
+	GetTwilioAccountSID() string
+	GetTwilioAuthToken() string
+	GetTwilioFromNumber() string
+	GetTwilioTimeout() time.Duration
+	GetTwilioRateLimitEnabled() bool
+	GetTwilioRateLimitMaxRequest() int
+	GetTwilioRateLimitWindowSize() time.Duration
+}
+
+func (s sms) GetEnabled() bool {
+	return s.Enabled
+}
+
+func (s sms) GetTwilioBaseURL() string {
+	return s.Twilio.BaseURL
+}
+
+func (s sms) GetTwilioAccountSID() string {
+	return s.Twilio.AccountSID
+}
+
+func (s sms) GetTwilioAuthToken() string {
+	return s.Twilio.AuthToken
+}
+
+func (s sms) GetTwilioFromNumber() string {
+	return s.Twilio.FromNumber
+}
+
+func (s sms) GetTwilioTimeout() time.Duration {
+	return s.Twilio.Timeout
+}
+
+func (s sms) GetTwilioRateLimitEnabled() bool {
+	return s.Twilio.RateLimit.Enabled
+}
+
+func (s sms) GetTwilioRateLimitMaxRequest() int {
+	return s.Twilio.RateLimit.MaxRequests
+}
+
+func (s sms) GetTwilioRateLimitWindowSize() time.Duration {
+	return s.Twilio.RateLimit.WindowSize
+}
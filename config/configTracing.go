@@ -0,0 +1,33 @@
+package config
+
+type Tracing interface {
+	GetEnabled() bool
+	GetExporter() string
+	GetEndpoint() string
+	GetInsecure() bool
+	GetSampleRatio() float64
+}
+
+func (t tracing) GetEnabled() bool {
+	return t.Enabled
+}
+
+func (t tracing) GetExporter() string {
+	return t.Exporter
+}
+
+func (t tracing) GetEndpoint() string {
+	return t.Endpoint
+}
+
+func (t tracing) GetInsecure() bool {
+	return t.Insecure
+}
+
+// GetSampleRatio defaults to sampling every trace.
+func (t tracing) GetSampleRatio() float64 {
+	if t.SampleRatio == nil {
+		return 1
+	}
+	return *t.SampleRatio
+}
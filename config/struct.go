@@ -3,16 +3,23 @@ package config
 import "time"
 
 type app struct {
-	Application application `mapstructure:"application"`
-	Logger      logger      `mapstructure:"logger"`
-	User        user        `mapstructure:"user"`
-	Kafka       kafka       `mapstructure:"kafka"`
-	Email       email       `mapstructure:"email"`
+	Application    application    `mapstructure:"application"`
+	Logger         logger         `mapstructure:"logger"`
+	Notifications  notifications  `mapstructure:"notifications"`
+	Kafka          kafka          `mapstructure:"kafka"`
+	Email          email          `mapstructure:"email"`
+	SMS            sms            `mapstructure:"sms"`
+	Dedup          dedup          `mapstructure:"dedup"`
+	HTTP           http           `mapstructure:"http"`
+	Tracing        tracing        `mapstructure:"tracing"`
+	API            api            `mapstructure:"api"`
+	RateLimitStore rateLimitStore `mapstructure:"rateLimitStore"`
 }
 
 // Application section
 type application struct {
-	Name string `mapstructure:"name"`
+	Name            string        `mapstructure:"name"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdownTimeout"`
 }
 
 // Logger section
@@ -28,31 +35,143 @@ type logger struct {
 
 // Kafka section
 type kafka struct {
-	Brokers []string `mapstructure:"brokers"`
-	Topics  struct {
-		Activation    string `mapstructure:"activation"`
-		PasswordReset string `mapstructure:"passwordReset"`
-	} `mapstructure:"topics"`
-	ConsumerGroupName string `mapstructure:"consumerGroupName"`
+	Brokers           []string `mapstructure:"brokers"`
+	ConsumerGroupName string   `mapstructure:"consumerGroupName"`
+	DeadLetter        struct {
+		Enabled bool `mapstructure:"enabled"`
+	} `mapstructure:"deadLetter"`
 }
 
-type user struct {
-	Activation struct {
-		TemplatePath string `mapstructure:"templatePath"`
-	} `mapstructure:"activation"`
-	PasswordReset struct {
-		TemplatePath string `mapstructure:"templatePath"`
-	} `mapstructure:"passwordReset"`
+// Notifications section, the registry of notification types and their shared settings
+type notifications struct {
+	Types     []notificationType `mapstructure:"types"`
+	InlineCSS bool               `mapstructure:"inlineCss"`
+	Layout    struct {
+		Path        string `mapstructure:"path"`
+		PartialsDir string `mapstructure:"partialsDir"`
+	} `mapstructure:"layout"`
+	Retry struct {
+		MaxAttempts int           `mapstructure:"maxAttempts"`
+		BaseDelay   time.Duration `mapstructure:"baseDelay"`
+		MaxDelay    time.Duration `mapstructure:"maxDelay"`
+		Jitter      float64       `mapstructure:"jitter"`
+	} `mapstructure:"retry"`
+	TemplateReload struct {
+		Enabled  bool          `mapstructure:"enabled"`
+		Debounce time.Duration `mapstructure:"debounce"`
+	} `mapstructure:"templateReload"`
+}
+
+type notificationType struct {
+	Type            string   `mapstructure:"type"`
+	Topic           string   `mapstructure:"topic"`
+	DeadLetterTopic string   `mapstructure:"deadLetterTopic"`
+	Channel         string   `mapstructure:"channel"`
+	RequiredMacros  []string `mapstructure:"requiredMacros"`
+	Templates       struct {
+		Body      string `mapstructure:"body"`
+		Text      string `mapstructure:"text"`
+		Subject   string `mapstructure:"subject"`
+		LocaleDir string `mapstructure:"localeDir"`
+	} `mapstructure:"templates"`
+	InlineCSS          bool               `mapstructure:"inlineCss"`
+	RateLimit          rateLimit          `mapstructure:"rateLimit"`
+	RecipientRateLimit recipientRateLimit `mapstructure:"recipientRateLimit"`
 }
 
 type email struct {
-	Mailjet struct {
+	Provider  string   `mapstructure:"provider"`
+	Providers []string `mapstructure:"providers"`
+	Mailjet   struct {
 		APIKey    string    `mapstructure:"apiKey"`
 		APISecret string    `mapstructure:"apiSecret"`
 		FromEmail string    `mapstructure:"fromEmail"`
 		FromName  string    `mapstructure:"fromName"`
 		RateLimit rateLimit `mapstructure:"rateLimit"`
 	} `mapstructure:"mailjet"`
+	SMTP struct {
+		Host       string        `mapstructure:"host"`
+		Port       int           `mapstructure:"port"`
+		Username   string        `mapstructure:"username"`
+		Password   string        `mapstructure:"password"`
+		FromEmail  string        `mapstructure:"fromEmail"`
+		FromName   string        `mapstructure:"fromName"`
+		TLSMode    string        `mapstructure:"tlsMode"`
+		AuthMethod string        `mapstructure:"authMethod"`
+		Timeout    time.Duration `mapstructure:"timeout"`
+		RateLimit  rateLimit     `mapstructure:"rateLimit"`
+	} `mapstructure:"smtp"`
+}
+
+type sms struct {
+	Enabled bool `mapstructure:"enabled"`
+	Twilio  struct {
+		BaseURL    string        `mapstructure:"baseURL"`
+		AccountSID string        `mapstructure:"accountSid"`
+		AuthToken  string        `mapstructure:"authToken"`
+		FromNumber string        `mapstructure:"fromNumber"`
+		Timeout    time.Duration `mapstructure:"timeout"`
+		RateLimit  rateLimit     `mapstructure:"rateLimit"`
+	} `mapstructure:"twilio"`
+}
+
+type dedup struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Store   string        `mapstructure:"store"`
+	Path    string        `mapstructure:"path"`
+	Window  time.Duration `mapstructure:"window"`
+}
+
+// HTTP section, serves the probe and metrics endpoints
+type http struct {
+	Port        int    `mapstructure:"port"`
+	HealthPath  string `mapstructure:"healthPath"`
+	ReadyPath   string `mapstructure:"readyPath"`
+	MetricsPath string `mapstructure:"metricsPath"`
+}
+
+// Tracing section, exports OpenTelemetry spans
+type tracing struct {
+	Enabled     bool     `mapstructure:"enabled"`
+	Exporter    string   `mapstructure:"exporter"`
+	Endpoint    string   `mapstructure:"endpoint"`
+	Insecure    bool     `mapstructure:"insecure"`
+	SampleRatio *float64 `mapstructure:"sampleRatio"`
+}
+
+// API section, accepts notifications directly instead of through Kafka
+type api struct {
+	Port           int           `mapstructure:"port"`
+	GRPCPort       int           `mapstructure:"grpcPort"`
+	Token          string        `mapstructure:"token"`
+	RequestTimeout time.Duration `mapstructure:"requestTimeout"`
+	MaxBatchSize   int           `mapstructure:"maxBatchSize"`
+	StatusTTL      time.Duration `mapstructure:"statusTtl"`
+	TLS            struct {
+		CertFile string `mapstructure:"certFile"`
+		KeyFile  string `mapstructure:"keyFile"`
+	} `mapstructure:"tls"`
+}
+
+// Rate limit store section, shares the rate limits across worker replicas
+type rateLimitStore struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Address       string        `mapstructure:"address"`
+	Password      string        `mapstructure:"password"`
+	DB            int           `mapstructure:"db"`
+	KeyPrefix     string        `mapstructure:"keyPrefix"`
+	Timeout       time.Duration `mapstructure:"timeout"`
+	RetryInterval time.Duration `mapstructure:"retryInterval"`
+}
+
+// recipientRateLimit limits the notifications of a type sent to one recipient
+type recipientRateLimit struct {
+	Enabled     bool          `mapstructure:"enabled"`
+	Algorithm   string        `mapstructure:"algorithm"`
+	MaxRequests int           `mapstructure:"maxRequests"`
+	WindowSize  time.Duration `mapstructure:"windowSize"`
+	Action      string        `mapstructure:"action"`
+	MaxDelay    time.Duration `mapstructure:"maxDelay"`
 }
 
 type rateLimit struct {
@@ -0,0 +1,59 @@
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// maxLineSize bounds a single exported dead letter, payloads can carry large macros.
+const maxLineSize = 4 * 1024 * 1024
+
+type reader struct {
+	path string
+}
+
+// NewReader creates a reader for dead letters exported as JSON lines.
+func NewReader(path string) *reader {
+	return &reader{path: path}
+}
+
+// ReadDeadLetters calls fn for each non-empty line of the file.
+func (r *reader) ReadDeadLetters(ctx context.Context, fn func(port.DeadLetter) error) error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		var deadLetter port.DeadLetter
+		if err := json.Unmarshal(raw, &deadLetter); err != nil {
+			return fmt.Errorf("failed to unmarshal dead letter on line %d: %w", line, err)
+		}
+		if err := fn(deadLetter); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
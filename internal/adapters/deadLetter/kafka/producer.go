@@ -0,0 +1,71 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/IBM/sarama"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+type producer struct {
+	producer sarama.SyncProducer
+	topics   map[string]string // source topic -> dead-letter topic
+}
+
+// New creates a Kafka producer that publishes dead letters to the topic configured
+// for the source topic the message was consumed from.
+func New(brokers []string, clientID string, topics map[string]string) (*producer, error) {
+	kConfig := sarama.NewConfig()
+	kConfig.ClientID = clientID
+	kConfig.Version = sarama.V2_1_0_0
+
+	// Dead letters must not be lost silently, wait for all in-sync replicas
+	kConfig.Producer.Return.Successes = true
+	kConfig.Producer.RequiredAcks = sarama.WaitForAll
+	kConfig.Producer.Retry.Max = 5
+
+	syncProducer, err := sarama.NewSyncProducer(brokers, kConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter producer: %w", err)
+	}
+
+	return &producer{
+		producer: syncProducer,
+		topics:   topics,
+	}, nil
+}
+
+// PublishDeadLetter publishes the failed message to its dead-letter topic.
+func (p *producer) PublishDeadLetter(ctx context.Context, msg port.DeadLetter) error {
+	topic, ok := p.topics[msg.SourceTopic]
+	if !ok || topic == "" {
+		return fmt.Errorf("no dead-letter topic configured for source topic %s", msg.SourceTopic)
+	}
+
+	value, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	record := &sarama.ProducerMessage{
+		Topic: topic,
+		Value: sarama.ByteEncoder(value),
+	}
+	if msg.Key != "" {
+		record.Key = sarama.StringEncoder(msg.Key)
+	}
+
+	if _, _, err := p.producer.SendMessage(record); err != nil {
+		return fmt.Errorf("failed to publish dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes and closes the underlying producer.
+func (p *producer) Close() error {
+	return p.producer.Close()
+}
@@ -0,0 +1,114 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IBM/sarama"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// idleTimeout ends the read of a partition when no record arrives for that long. The
+// offsets below the high-water mark may be taken by compacted records or transaction
+// markers, which are never delivered.
+const idleTimeout = 5 * time.Second
+
+type reader struct {
+	client sarama.Client
+	topic  string
+}
+
+// NewReader creates a reader for every dead letter currently stored in the given topic.
+func NewReader(brokers []string, clientID, topic string) (*reader, error) {
+	kConfig := sarama.NewConfig()
+	kConfig.ClientID = clientID
+	kConfig.Version = sarama.V2_1_0_0
+
+	client, err := sarama.NewClient(brokers, kConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dead-letter reader: %w", err)
+	}
+
+	return &reader{
+		client: client,
+		topic:  topic,
+	}, nil
+}
+
+// ReadDeadLetters calls fn for each record from the oldest offset up to the high-water mark
+// at the time of the call, partition by partition. A partition is done once its last record
+// was read or no record arrived within the idle timeout. It does not commit any offsets.
+func (r *reader) ReadDeadLetters(ctx context.Context, fn func(port.DeadLetter) error) error {
+	consumer, err := sarama.NewConsumerFromClient(r.client)
+	if err != nil {
+		return err
+	}
+	defer consumer.Close()
+
+	partitions, err := r.client.Partitions(r.topic)
+	if err != nil {
+		return fmt.Errorf("failed to list partitions of %s: %w", r.topic, err)
+	}
+
+	for _, partition := range partitions {
+		if err := r.readPartition(ctx, consumer, partition, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *reader) readPartition(ctx context.Context, consumer sarama.Consumer, partition int32, fn func(port.DeadLetter) error) error {
+	oldest, err := r.client.GetOffset(r.topic, partition, sarama.OffsetOldest)
+	if err != nil {
+		return err
+	}
+	newest, err := r.client.GetOffset(r.topic, partition, sarama.OffsetNewest)
+	if err != nil {
+		return err
+	}
+	if newest <= oldest {
+		return nil
+	}
+
+	partitionConsumer, err := consumer.ConsumePartition(r.topic, partition, oldest)
+	if err != nil {
+		return err
+	}
+	defer partitionConsumer.Close()
+
+	idle := time.NewTimer(idleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-idle.C:
+			return nil
+		case err := <-partitionConsumer.Errors():
+			return err
+		case msg := <-partitionConsumer.Messages():
+			var deadLetter port.DeadLetter
+			if err := json.Unmarshal(msg.Value, &deadLetter); err != nil {
+				return fmt.Errorf("failed to unmarshal dead letter at partition %d offset %d: %w", partition, msg.Offset, err)
+			}
+			if err := fn(deadLetter); err != nil {
+				return err
+			}
+			if msg.Offset >= newest-1 {
+				return nil
+			}
+			idle.Reset(idleTimeout)
+		}
+	}
+}
+
+// Close closes the underlying Kafka client.
+func (r *reader) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,115 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// sweepInterval bounds how often expired keys are purged.
+const sweepInterval = 10 * time.Minute
+
+var bucketName = []byte("dedup")
+
+type store struct {
+	db *bolt.DB
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// New opens (or creates) a BoltDB file that persists deduplication keys across restarts.
+func New(path string) (*store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucketName)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &store{
+		db:        db,
+		lastSweep: time.Now(),
+	}, nil
+}
+
+// MarkIfAbsent records key for the given ttl, unless it is marked and has not expired yet.
+// It reports whether key was recorded. The check and the write share one transaction.
+func (s *store) MarkIfAbsent(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(now.Add(ttl).UnixNano()))
+
+	var marked bool
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+		if existing := bucket.Get([]byte(key)); len(existing) == 8 && now.Before(time.Unix(0, int64(binary.BigEndian.Uint64(existing)))) {
+			return nil
+		}
+
+		marked = true
+		return bucket.Put([]byte(key), value)
+	})
+	if err != nil || !marked {
+		return false, err
+	}
+
+	return true, s.sweep(now)
+}
+
+// Unmark removes key, so that the next delivery is processed again.
+func (s *store) Unmark(ctx context.Context, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketName).Delete([]byte(key))
+	})
+}
+
+// Close closes the underlying database file.
+func (s *store) Close() error {
+	return s.db.Close()
+}
+
+// sweep removes expired keys, at most once per sweepInterval.
+func (s *store) sweep(now time.Time) error {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) < sweepInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastSweep = now
+	s.mu.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(bucketName)
+
+		// Collect first, deleting while iterating a cursor skips entries
+		var expired [][]byte
+		err := bucket.ForEach(func(key, value []byte) error {
+			if len(value) != 8 || !now.Before(time.Unix(0, int64(binary.BigEndian.Uint64(value)))) {
+				expired = append(expired, append([]byte(nil), key...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, key := range expired {
+			if err := bucket.Delete(key); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
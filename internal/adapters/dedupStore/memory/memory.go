@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval bounds how often expired keys are purged.
+const sweepInterval = time.Minute
+
+type store struct {
+	mu        sync.Mutex
+	keys      map[string]time.Time // key -> expiry
+	lastSweep time.Time
+}
+
+// New creates an in-process deduplication store. Keys are lost on restart.
+func New() *store {
+	return &store{
+		keys:      make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}
+}
+
+// MarkIfAbsent records key for the given ttl, unless it is marked and has not expired yet.
+// It reports whether key was recorded.
+func (s *store) MarkIfAbsent(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiry, ok := s.keys[key]; ok && now.Before(expiry) {
+		return false, nil
+	}
+	s.keys[key] = now.Add(ttl)
+	s.sweep(now)
+
+	return true, nil
+}
+
+// Unmark removes key, so that the next delivery is processed again.
+func (s *store) Unmark(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+	return nil
+}
+
+// sweep removes expired keys, at most once per sweepInterval.
+func (s *store) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+
+	for key, expiry := range s.keys {
+		if !now.Before(expiry) {
+			delete(s.keys, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,101 @@
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// Provider is a single email provider in the failover chain.
+type Provider struct {
+	Name        string
+	Emailer     port.Emailer
+	RateLimiter port.RateLimiter // optional, a provider over its limit is skipped
+}
+
+type FailoverEmailer struct {
+	providers []Provider
+	logger    port.Logger
+}
+
+// New creates an emailer that tries each provider in the given priority order.
+func New(loggerIns port.Logger, providers ...Provider) *FailoverEmailer {
+	return &FailoverEmailer{
+		providers: providers,
+		logger:    loggerIns,
+	}
+}
+
+// SendEmail hands the message to the first provider that accepts it. Transient
+// failures move on to the next provider, permanent ones are returned immediately.
+// When every provider is over its rate limit, it waits for the first one instead.
+func (f *FailoverEmailer) SendEmail(ctx context.Context, to, subject, htmlBody, textBody string) error {
+	var errs []error
+	limited := 0
+	for _, provider := range f.providers {
+		if provider.RateLimiter != nil && !provider.RateLimiter.Allow() {
+			f.logger.Warnw(ctx, "Email provider rate limit reached, trying next provider", "provider", provider.Name, "to", to)
+			errs = append(errs, fmt.Errorf("%s: rate limit exceeded", provider.Name))
+			limited++
+			continue
+		}
+
+		done, err := f.send(ctx, provider, to, subject, htmlBody, textBody)
+		if done {
+			return err
+		}
+		errs = append(errs, err)
+	}
+
+	// Waiting for quota does not spend a retry attempt, unlike failing the message
+	if limited > 0 && limited == len(f.providers) {
+		provider := f.providers[0]
+		f.logger.Warnw(ctx, "All email providers rate limited, waiting for provider", "provider", provider.Name, "to", to)
+		if err := provider.RateLimiter.WaitUntilAllowed(ctx); err != nil {
+			return fmt.Errorf("%s: rate limit error: %w", provider.Name, err)
+		}
+
+		done, err := f.send(ctx, provider, to, subject, htmlBody, textBody)
+		if done {
+			return err
+		}
+		errs = append(errs, err)
+	}
+
+	return fmt.Errorf("all email providers failed: %w", errors.Join(errs...))
+}
+
+// send hands the message to provider. It reports done when the chain must stop, either
+// because the email was accepted or because it was rejected permanently.
+func (f *FailoverEmailer) send(ctx context.Context, provider Provider, to, subject, htmlBody, textBody string) (bool, error) {
+	err := provider.Emailer.SendEmail(ctx, to, subject, htmlBody, textBody)
+	if err == nil {
+		f.logger.Infow(ctx, "Email accepted by provider", "provider", provider.Name, "to", to)
+		return true, nil
+	}
+
+	if !utils.IsTransient(err) {
+		f.logger.Errorw(ctx, "Email rejected by provider", "provider", provider.Name, "to", to, "error", err)
+		return true, fmt.Errorf("%s: %w", provider.Name, err)
+	}
+
+	f.logger.Warnw(ctx, "Email provider failed, trying next provider", "provider", provider.Name, "to", to, "error", err)
+	return false, fmt.Errorf("%s: %w", provider.Name, err)
+}
+
+// Close closes the providers that hold a connection.
+func (f *FailoverEmailer) Close() error {
+	var errs []error
+	for _, provider := range f.providers {
+		if closer, ok := provider.Emailer.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", provider.Name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
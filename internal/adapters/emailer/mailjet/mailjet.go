@@ -1,8 +1,12 @@
 package mailjet
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
+	"github.com/loganrk/worker-engine/internal/utils"
 	"github.com/mailjet/mailjet-apiv3-go"
 )
 
@@ -21,7 +25,9 @@ func New(apiKey, apiSecret, from, fromName string) *MailjetEmailer {
 	}
 }
 
-func (m *MailjetEmailer) SendEmail(to, subject, body string) error {
+// SendEmail sends the message through the Mailjet v3.1 API. The SDK does not accept a
+// context, so ctx is not propagated to the HTTP call.
+func (m *MailjetEmailer) SendEmail(ctx context.Context, to, subject, htmlBody, textBody string) error {
 	toRecipients := mailjet.RecipientsV31{
 		mailjet.RecipientV31{
 			Email: to,
@@ -37,15 +43,15 @@ func (m *MailjetEmailer) SendEmail(to, subject, body string) error {
 			},
 			To:       &toRecipients,
 			Subject:  subject,
-			TextPart: body,
-			HTMLPart: body,
+			TextPart: textBody,
+			HTMLPart: htmlBody,
 		},
 	}
 
 	messages := mailjet.MessagesV31{Info: messagesInfo}
 	resp, err := m.Client.SendMailV31(&messages)
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 	// Check how many messages were successfully sent
 	if len(resp.ResultsV31) == 0 {
@@ -61,3 +67,22 @@ func (m *MailjetEmailer) SendEmail(to, subject, body string) error {
 
 	return nil
 }
+
+// classifyError marks validation and client errors returned by the API as permanent.
+// Authentication and throttling errors stay transient so another provider can be tried.
+func classifyError(err error) error {
+	var feedbackErr *mailjet.APIFeedbackErrorsV31
+	if errors.As(err, &feedbackErr) {
+		return utils.Permanent(err)
+	}
+
+	var infoErr *mailjet.ErrorInfoV31
+	if errors.As(err, &infoErr) {
+		status := infoErr.StatusCode
+		if status >= 400 && status < 500 && status != http.StatusUnauthorized && status != http.StatusTooManyRequests {
+			return utils.Permanent(err)
+		}
+	}
+
+	return err
+}
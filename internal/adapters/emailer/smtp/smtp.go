@@ -0,0 +1,310 @@
+package smtp
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+const (
+	TLS_MODE_STARTTLS = "starttls"
+	TLS_MODE_IMPLICIT = "implicit"
+	TLS_MODE_NONE     = "none"
+
+	AUTH_PLAIN = "plain"
+	AUTH_LOGIN = "login"
+)
+
+type Config struct {
+	Host       string
+	Port       int
+	Username   string
+	Password   string
+	From       string
+	FromName   string
+	TLSMode    string        // starttls (default), implicit or none
+	AuthMethod string        // plain (default) or login; ignored when Username is empty
+	Timeout    time.Duration // dial and per-command timeout
+}
+
+type SMTPEmailer struct {
+	conf Config
+
+	mu     sync.Mutex
+	client *smtp.Client // connection kept open between sends
+	conn   net.Conn
+}
+
+// New creates an SMTP emailer. The connection is opened lazily on the first send
+// and reused for subsequent messages until the server drops it.
+func New(conf Config) *SMTPEmailer {
+	if conf.TLSMode == "" {
+		conf.TLSMode = TLS_MODE_STARTTLS
+	}
+	if conf.AuthMethod == "" {
+		conf.AuthMethod = AUTH_PLAIN
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = 30 * time.Second
+	}
+
+	return &SMTPEmailer{conf: conf}
+}
+
+// SendEmail delivers the message over the pooled connection. A deadline on ctx shortens
+// the configured per-command timeout.
+func (s *SMTPEmailer) SendEmail(ctx context.Context, to, subject, htmlBody, textBody string) error {
+	msg, err := s.buildMessage(to, subject, htmlBody, textBody)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A reused connection may have been dropped by the server while idle, so reconnect if RSET fails
+	if s.client != nil {
+		s.conn.SetDeadline(s.deadline(ctx))
+		if err := s.client.Reset(); err != nil {
+			s.closeLocked()
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if s.client == nil {
+		if err := s.connectLocked(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := s.sendLocked(ctx, to, msg); err != nil {
+		s.closeLocked()
+		return err
+	}
+
+	return nil
+}
+
+// Close terminates the pooled connection, if any.
+func (s *SMTPEmailer) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Quit()
+	s.closeLocked()
+	return err
+}
+
+func (s *SMTPEmailer) connectLocked(ctx context.Context) error {
+	addr := net.JoinHostPort(s.conf.Host, strconv.Itoa(s.conf.Port))
+	tlsConfig := &tls.Config{ServerName: s.conf.Host}
+	dialer := &net.Dialer{Timeout: s.conf.Timeout}
+
+	var conn net.Conn
+	var err error
+	if s.conf.TLSMode == TLS_MODE_IMPLICIT {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: tlsConfig}
+		conn, err = tlsDialer.DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	conn.SetDeadline(s.deadline(ctx))
+
+	client, err := smtp.NewClient(conn, s.conf.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to create smtp client: %w", err)
+	}
+
+	if s.conf.TLSMode == TLS_MODE_STARTTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if s.conf.Username != "" {
+		var auth smtp.Auth
+		if s.conf.AuthMethod == AUTH_LOGIN {
+			auth = &loginAuth{username: s.conf.Username, password: s.conf.Password}
+		} else {
+			auth = smtp.PlainAuth("", s.conf.Username, s.conf.Password, s.conf.Host)
+		}
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return fmt.Errorf("smtp authentication failed: %w", err)
+		}
+	}
+
+	s.client = client
+	s.conn = conn
+	return nil
+}
+
+func (s *SMTPEmailer) sendLocked(ctx context.Context, to string, msg []byte) error {
+	s.conn.SetDeadline(s.deadline(ctx))
+
+	if err := s.client.Mail(s.conf.From); err != nil {
+		return classifyError(err)
+	}
+	if err := s.client.Rcpt(to); err != nil {
+		return classifyError(err)
+	}
+
+	w, err := s.client.Data()
+	if err != nil {
+		return classifyError(err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		w.Close()
+		return err
+	}
+
+	return classifyError(w.Close())
+}
+
+// deadline returns the earlier of the configured timeout and the deadline of ctx.
+func (s *SMTPEmailer) deadline(ctx context.Context) time.Time {
+	deadline := time.Now().Add(s.conf.Timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		return ctxDeadline
+	}
+	return deadline
+}
+
+func (s *SMTPEmailer) closeLocked() {
+	if s.client != nil {
+		s.client.Close()
+	}
+	s.client = nil
+	s.conn = nil
+}
+
+// buildMessage renders the RFC 5322 message as a multipart/alternative with quoted-printable
+// text and HTML parts, or with the HTML part only when there is no text body.
+func (s *SMTPEmailer) buildMessage(to, subject, htmlBody, textBody string) ([]byte, error) {
+	if _, err := mail.ParseAddress(to); err != nil {
+		return nil, utils.Permanent(fmt.Errorf("invalid recipient address %q: %w", to, err))
+	}
+
+	from := mail.Address{Name: s.conf.FromName, Address: s.conf.From}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+
+	if textBody == "" {
+		fmt.Fprintf(&buf, "Content-Type: text/html; charset=\"utf-8\"\r\n")
+		fmt.Fprintf(&buf, "Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+		if err := writeQuotedPrintable(&buf, htmlBody); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", parts.Boundary())
+
+	// Clients display the last part they support, so the HTML part goes last
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain", textBody},
+		{"text/html", htmlBody},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + "; charset=\"utf-8\""},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w io.Writer, body string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(body)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// classifyError marks 5xx replies to a message transaction as permanent, the server
+// has rejected the sender, recipient or content and will do so again.
+func classifyError(err error) error {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) && protoErr.Code >= 500 {
+		return utils.Permanent(err)
+	}
+	return err
+}
+
+// loginAuth implements the non-standard but widely deployed AUTH LOGIN mechanism.
+type loginAuth struct {
+	username string
+	password string
+}
+
+func (a *loginAuth) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	if !server.TLS && !isLocalhost(server.Name) {
+		return "", nil, errors.New("unencrypted connection")
+	}
+	return "LOGIN", nil, nil
+}
+
+func (a *loginAuth) Next(fromServer []byte, more bool) ([]byte, error) {
+	if !more {
+		return nil, nil
+	}
+
+	switch string(fromServer) {
+	case "Username:", "username:":
+		return []byte(a.username), nil
+	case "Password:", "password:":
+		return []byte(a.password), nil
+	default:
+		return nil, fmt.Errorf("unexpected server challenge: %s", fromServer)
+	}
+}
+
+func isLocalhost(name string) bool {
+	return name == "localhost" || name == "127.0.0.1" || name == "::1"
+}
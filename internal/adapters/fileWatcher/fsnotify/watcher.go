@@ -0,0 +1,111 @@
+package fsnotify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// defaultDebounce applies when no debounce is configured.
+const defaultDebounce = 500 * time.Millisecond
+
+type watcher struct {
+	logger   port.Logger
+	watcher  *fsnotify.Watcher
+	debounce time.Duration
+
+	wg sync.WaitGroup // running event loops
+}
+
+// New creates a watcher that reports changes once no further event arrived for debounce.
+func New(loggerIns port.Logger, debounce time.Duration) (*watcher, error) {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+
+	if debounce <= 0 {
+		debounce = defaultDebounce
+	}
+
+	return &watcher{
+		logger:   loggerIns,
+		watcher:  fsWatcher,
+		debounce: debounce,
+	}, nil
+}
+
+// Watch calls onChange after files at paths, or in the directories at paths, are written,
+// created, renamed or removed, until ctx is cancelled. The parent directories of files are
+// watched rather than the files, so editors that save through a rename and symlink swaps
+// of mounted volumes are noticed too.
+func (w *watcher) Watch(ctx context.Context, paths []string, onChange func()) error {
+	dirs := make(map[string]bool)
+	for _, path := range paths {
+		dir := path
+		if info, err := os.Stat(path); err != nil || !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+		if dirs[dir] {
+			continue
+		}
+		if err := w.watcher.Add(dir); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", dir, err)
+		}
+		dirs[dir] = true
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		var timer *time.Timer
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-w.watcher.Events:
+				if !ok {
+					return
+				}
+				if event.Op == fsnotify.Chmod {
+					continue
+				}
+
+				// Editors emit several events per save, wait for them to settle
+				if timer == nil {
+					timer = time.AfterFunc(w.debounce, onChange)
+				} else {
+					timer.Reset(w.debounce)
+				}
+			case err, ok := <-w.watcher.Errors:
+				if !ok {
+					return
+				}
+				w.logger.Warnw(ctx, "file watcher error", "error", err)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close stops watching and waits for the event loop to exit.
+func (w *watcher) Close() error {
+	err := w.watcher.Close()
+	w.wg.Wait()
+	return err
+}
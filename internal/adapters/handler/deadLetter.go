@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// publishDeadLetter publishes the message carried by err to the dead-letter topic.
+// Consumer-level errors that are not tied to a message are only logged by the caller.
+func (h *handler) publishDeadLetter(ctx context.Context, err error) error {
+	if h.deadLetter == nil {
+		return nil
+	}
+
+	var msgErr *port.MessageError
+	if !errors.As(err, &msgErr) {
+		return nil
+	}
+
+	attempts := 1
+	var retryErr *utils.RetryError
+	if errors.As(msgErr.Err, &retryErr) {
+		attempts = retryErr.Attempts
+	}
+
+	deadLetter := port.DeadLetter{
+		SourceTopic: msgErr.Topic,
+		Partition:   msgErr.Partition,
+		Offset:      msgErr.Offset,
+		Key:         string(msgErr.Key),
+		Payload:     string(msgErr.Payload),
+		Error:       msgErr.Err.Error(),
+		Attempts:    attempts,
+		FailedAt:    time.Now().UTC(),
+	}
+
+	if err := h.deadLetter.PublishDeadLetter(ctx, deadLetter); err != nil {
+		h.logger.Errorw(ctx, "Failed to publish dead letter", "topic", msgErr.Topic, "offset", msgErr.Offset, "error", err)
+		return fmt.Errorf("failed to publish dead letter: %w", err)
+	}
+
+	h.logger.Infow(ctx, "Published dead letter", "topic", msgErr.Topic, "offset", msgErr.Offset, "attempts", attempts)
+	return nil
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// idempotencyKey returns the key supplied by the producer, or derives one from the
+// message content so that a redelivered message maps to the same key.
+func idempotencyKey(msg port.Message) string {
+	if msg.IdempotencyKey != "" {
+		return msg.IdempotencyKey
+	}
+
+	keys := make([]string, 0, len(msg.Macros))
+	for key := range msg.Macros {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	hash := sha256.New()
+	for _, part := range []string{msg.Type, msg.To, msg.Subject} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	for _, key := range keys {
+		hash.Write([]byte(key))
+		hash.Write([]byte{0})
+		hash.Write([]byte(msg.Macros[key]))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// reserve atomically records the key before the message is handled, so that a concurrent
+// copy of the message is skipped. It reports false when the key was already recorded within
+// the dedup window. Store errors are logged and treated as unseen, a duplicate beats a lost
+// notification.
+func (h *handler) reserve(ctx context.Context, key string) bool {
+	if h.dedup == nil {
+		return true
+	}
+
+	marked, err := h.dedup.MarkIfAbsent(ctx, key, h.dedupWindow)
+	if err != nil {
+		h.logger.Warnw(ctx, "Failed to record idempotency key", "key", key, "error", err)
+		return true
+	}
+	return marked
+}
+
+// release removes the key of a message that failed, so that its redelivery is processed.
+func (h *handler) release(ctx context.Context, key string) {
+	if h.dedup == nil {
+		return
+	}
+
+	if err := h.dedup.Unmark(ctx, key); err != nil {
+		h.logger.Warnw(ctx, "Failed to release idempotency key", "key", key, "error", err)
+	}
+}
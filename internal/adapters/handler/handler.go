@@ -1,18 +1,71 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
 	"github.com/loganrk/worker-engine/internal/core/port"
 )
 
 type handler struct {
-	usecases port.SvrList // List of usecases (services) to handle business logic
-	logger   port.Logger  // Logger instance for logging messages
+	usecases    port.SvrList             // List of usecases (services) to handle business logic
+	logger      port.Logger              // Logger instance for logging messages
+	deadLetter  port.DeadLetterPublisher // Optional publisher for messages that failed processing
+	dedup       port.DedupStore          // Optional store of already processed idempotency keys
+	dedupWindow time.Duration            // How long a processed key suppresses redeliveries
+
+	mu       sync.Mutex     // guards draining against new in-flight calls
+	draining bool           // set once Drain is called, new messages are refused
+	inflight sync.WaitGroup // messages currently being processed
 }
 
-// New creates and returns a new handler instance with the provided logger and service list.
-func New(loggerIns port.Logger, svcList port.SvrList) *handler {
+// errDraining is returned for messages that arrive after Drain was called.
+var errDraining = errors.New("handler is shutting down")
+
+// New creates and returns a new handler instance with the provided logger, service list,
+// dead-letter publisher and dedup store. A nil publisher only logs failed messages and a
+// nil dedup store processes every delivery.
+func New(loggerIns port.Logger, svcList port.SvrList, deadLetterIns port.DeadLetterPublisher, dedupIns port.DedupStore, dedupWindow time.Duration) *handler {
 	return &handler{
-		usecases: svcList,   // List of services that will handle specific business logic
-		logger:   loggerIns, // Logger for capturing logs
+		usecases:    svcList,       // List of services that will handle specific business logic
+		logger:      loggerIns,     // Logger for capturing logs
+		deadLetter:  deadLetterIns, // Dead-letter publisher for failed messages
+		dedup:       dedupIns,      // Dedup store for redelivered messages
+		dedupWindow: dedupWindow,
+	}
+}
+
+// Drain stops accepting new messages and waits until the in-flight ones, including their
+// sends and rate-limiter waits, have finished or ctx is done.
+func (h *handler) Drain(ctx context.Context) error {
+	h.mu.Lock()
+	h.draining = true
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// begin registers an in-flight message, it returns false once the handler is draining.
+func (h *handler) begin() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.draining {
+		return false
 	}
+	h.inflight.Add(1)
+	return true
 }
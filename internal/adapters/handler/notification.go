@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// Handle processes a notification of any registered type.
+func (h *handler) Handle(ctx context.Context, msg port.Message) error {
+	if !h.begin() {
+		return errDraining
+	}
+	defer h.inflight.Done()
+
+	key := idempotencyKey(msg)
+	if !h.reserve(ctx, key) {
+		h.logger.Infow(ctx, "Skipping duplicate notification", "type", msg.Type, "to", msg.To, "key", key)
+		return nil
+	}
+
+	err := h.usecases.Notification.Send(ctx, msg)
+	if err != nil {
+		h.release(ctx, key)
+		h.logger.Errorw(ctx, "Failed to process notification", "type", msg.Type, "error", err)
+		return err
+	}
+
+	h.logger.Infow(ctx, "Successfully processed notification", "type", msg.Type, "to", msg.To)
+	return nil
+}
+
+// HandleError logs errors that occur in the Kafka consumer pipeline and dead-letters the
+// failed message, if any. Messages dropped on purpose are only logged. It returns an error
+// when the message could not be dead-lettered, so that it is not committed.
+func (h *handler) HandleError(ctx context.Context, err error) error {
+	if errors.Is(err, utils.ErrDropped) {
+		h.logger.Warnw(ctx, "Dropped message in Consumer", "error", err)
+		return nil
+	}
+
+	h.logger.Errorw(ctx, "Error in Consumer", "error", err)
+	return h.publishDeadLetter(ctx, err)
+}
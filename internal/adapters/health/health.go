@@ -0,0 +1,69 @@
+package health
+
+import (
+	"sync"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+type health struct {
+	mu           sync.RWMutex
+	components   map[string]bool // component name -> initialized
+	shuttingDown bool
+}
+
+// New creates a readiness tracker that only reports ready once every listed component
+// has been marked ready.
+func New(components ...string) *health {
+	h := &health{components: make(map[string]bool, len(components))}
+	for _, component := range components {
+		h.components[component] = false
+	}
+	return h
+}
+
+// SetReady marks a component as initialized.
+func (h *health) SetReady(component string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.components[component] = true
+}
+
+// SetNotReady marks a component as unavailable again, e.g. after losing its connection.
+func (h *health) SetNotReady(component string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.components[component] = false
+}
+
+// SetShuttingDown makes readiness fail from now on so no new traffic is routed here.
+func (h *health) SetShuttingDown() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.shuttingDown = true
+}
+
+// Readiness reports ready once every component is initialized, until shutdown starts.
+func (h *health) Readiness() port.HealthStatus {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	components := make(map[string]bool, len(h.components))
+	for component, ready := range h.components {
+		components[component] = ready
+	}
+
+	if h.shuttingDown {
+		return port.HealthStatus{State: port.HEALTH_SHUTTING_DOWN, Components: components}
+	}
+	for _, ready := range components {
+		if !ready {
+			return port.HealthStatus{State: port.HEALTH_UNAVAILABLE, Components: components}
+		}
+	}
+
+	return port.HealthStatus{Ready: true, State: port.HEALTH_OK, Components: components}
+}
@@ -0,0 +1,41 @@
+package httpServer
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// probeStatus is the JSON body returned by the probe endpoints.
+type probeStatus struct {
+	Status     string          `json:"status"`
+	Components map[string]bool `json:"components,omitempty"`
+}
+
+// Liveness answers the liveness probe, it succeeds as long as the process serves HTTP.
+func Liveness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, http.StatusOK, probeStatus{Status: port.HEALTH_OK})
+	})
+}
+
+// Readiness answers the readiness probe with the state of every component.
+func Readiness(healthIns port.Health) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := healthIns.Readiness()
+
+		code := http.StatusOK
+		if !status.Ready {
+			code = http.StatusServiceUnavailable
+		}
+		writeStatus(w, code, probeStatus{Status: status.State, Components: status.Components})
+	})
+}
+
+func writeStatus(w http.ResponseWriter, code int, body probeStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,74 @@
+package httpServer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type server struct {
+	mux *http.ServeMux
+	srv *http.Server
+}
+
+// New creates an HTTP server listening on the given port of every interface. Routes are added
+// with Handle before Start is called.
+func New(port int) *server {
+	return NewHost("", port)
+}
+
+// NewHost creates an HTTP server like New that only listens on the given host, e.g. 127.0.0.1.
+func NewHost(host string, port int) *server {
+	mux := http.NewServeMux()
+	return &server{
+		mux: mux,
+		srv: &http.Server{
+			Addr:              net.JoinHostPort(host, strconv.Itoa(port)),
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}
+}
+
+// NewTLS creates an HTTP server like New that serves HTTPS with the given TLS config, a nil
+// config serves plain HTTP.
+func NewTLS(port int, tlsConfig *tls.Config) *server {
+	s := New(port)
+	s.srv.TLSConfig = tlsConfig
+	return s
+}
+
+// Handle registers a handler for the given path.
+func (s *server) Handle(path string, handler http.Handler) {
+	s.mux.Handle(path, handler)
+}
+
+// Start binds the port and serves requests in the background.
+func (s *server) Start(errorHandler func(error)) error {
+	listener, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		serve := s.srv.Serve
+		if s.srv.TLSConfig != nil {
+			// The certificates are taken from the TLS config
+			serve = func(listener net.Listener) error { return s.srv.ServeTLS(listener, "", "") }
+		}
+
+		if err := serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errorHandler(err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops accepting connections and waits for active requests until ctx is done.
+func (s *server) Shutdown(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
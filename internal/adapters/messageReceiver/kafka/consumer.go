@@ -0,0 +1,288 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// message is the structure expected from Kafka.
+type message struct {
+	IdempotencyKey string            `json:"idempotencyKey"` // optional, deduplicates redeliveries
+	Type           string            `json:"type"`           // e.g., "verification-email", "password-reset-phone"
+	To             string            `json:"to"`             // email or phone
+	Subject        string            `json:"subject"`        // optional, overrides the subject template
+	Locale         string            `json:"locale"`         // optional, e.g. "pt-BR"
+	Macros         map[string]string `json:"macros"`         // template variables
+}
+
+func (m message) toPort() port.Message {
+	return port.Message{
+		IdempotencyKey: m.IdempotencyKey,
+		Type:           m.Type,
+		To:             m.To,
+		Subject:        m.Subject,
+		Locale:         m.Locale,
+		Macros:         m.Macros,
+	}
+}
+
+// DecodeMessage decodes the value of a record into a notification message. Dead letters keep
+// the original value, so replaying them decodes it the same way.
+func DecodeMessage(value []byte) (port.Message, error) {
+	if len(value) == 0 {
+		return port.Message{}, fmt.Errorf("%w: empty message (EOF)", utils.ErrInvalidMessage)
+	}
+
+	var msg message
+	if err := json.Unmarshal(value, &msg); err != nil {
+		return port.Message{}, fmt.Errorf("%w: %s, error: %v", utils.ErrInvalidMessage, string(value), err)
+	}
+	return msg.toPort(), nil
+}
+
+// consumer is a Kafka adapter that consumes every registered topic in one consumer group
+// and routes each message to the handler registered for its topic.
+type consumer struct {
+	handlers      map[string]func(ctx context.Context, msg port.Message) error // handlers keyed by topic
+	consumerGroup sarama.ConsumerGroup
+
+	groupID      string
+	brokers      []string
+	saramaConfig *sarama.Config
+
+	wg sync.WaitGroup // running consume loop
+}
+
+// New initializes the consumer with the provided Kafka connection details.
+func New(brokers []string, groupID string) *consumer {
+	cfg := sarama.NewConfig()
+	cfg.Consumer.Offsets.Initial = sarama.OffsetNewest
+	cfg.Version = sarama.V2_1_0_0
+
+	return &consumer{
+		handlers:     make(map[string]func(ctx context.Context, msg port.Message) error),
+		groupID:      groupID,
+		brokers:      brokers,
+		saramaConfig: cfg,
+	}
+}
+
+// Register sets the handler for the messages of a topic. It must be called before Listen.
+func (c *consumer) Register(topic string, handler func(ctx context.Context, msg port.Message) error) error {
+	if _, ok := c.handlers[topic]; ok {
+		return fmt.Errorf("topic %s is already registered", topic)
+	}
+	c.handlers[topic] = handler
+	return nil
+}
+
+// Listen starts consuming the registered topics in the background. readyHandler is called with
+// true once the consumer group is joined and partitions are assigned, and with false while
+// consuming fails.
+func (c *consumer) Listen(ctx context.Context, errorHandler func(context.Context, error) error, readyHandler func(ready bool)) error {
+	if len(c.handlers) == 0 {
+		return errors.New("no topics registered")
+	}
+
+	consumerGroup, err := sarama.NewConsumerGroup(c.brokers, c.groupID, c.saramaConfig)
+	if err != nil {
+		return err
+	}
+	c.consumerGroup = consumerGroup
+
+	topics := make([]string, 0, len(c.handlers))
+	for topic := range c.handlers {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for failures := 0; ; {
+			err := consumerGroup.Consume(ctx, topics, &consumerHandler{
+				messageHandler: c.route,
+				errorHandler:   errorHandler,
+				readyHandler:   readyHandler,
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			if err == nil {
+				// The session ended on a rebalance, join again right away
+				failures = 0
+				continue
+			}
+
+			// The error is not tied to a record, so there is nothing to commit or redeliver
+			// whatever the error handler returns. Back off so that a broker that stays down
+			// does not flood the error handler.
+			failures++
+			readyHandler(false)
+			_ = errorHandler(ctx, err)
+
+			timer := time.NewTimer(errorHandlerBackoff.Backoff(failures))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// route decodes a message and hands it to the handler registered for its topic.
+func (c *consumer) route(ctx context.Context, topic string, msgBytes []byte) error {
+	msg, err := DecodeMessage(msgBytes)
+	if err != nil {
+		return fmt.Errorf("failed to decode message on %s: %w", topic, err)
+	}
+
+	handler, ok := c.handlers[topic]
+	if !ok {
+		return fmt.Errorf("no handler registered for topic %s", topic)
+	}
+	return handler(ctx, msg)
+}
+
+// Close waits for the consume loop to stop and closes the consumer group. The context
+// passed to Listen must be cancelled first, otherwise Close blocks.
+func (c *consumer) Close() error {
+	c.wg.Wait()
+
+	if c.consumerGroup == nil {
+		return nil
+	}
+	if err := c.consumerGroup.Close(); err != nil && !errors.Is(err, sarama.ErrClosedConsumerGroup) {
+		return err
+	}
+	return nil
+}
+
+// errorHandlerBackoff spaces the attempts to hand a failed record to the error handler, and
+// the attempts to consume again after consuming failed.
+var errorHandlerBackoff = utils.RetryPolicy{
+	BaseDelay: time.Second,
+	MaxDelay:  30 * time.Second,
+	Jitter:    0.2,
+}
+
+// consumerHandler delegates messages to a handler.
+type consumerHandler struct {
+	messageHandler func(ctx context.Context, topic string, msgBytes []byte) error
+	errorHandler   func(context.Context, error) error
+	readyHandler   func(ready bool)
+}
+
+// Setup runs once the group is joined and the partitions of this member are assigned.
+func (h *consumerHandler) Setup(sarama.ConsumerGroupSession) error {
+	h.readyHandler(true)
+	return nil
+}
+
+func (h *consumerHandler) Cleanup(sarama.ConsumerGroupSession) error { return nil }
+
+// ConsumeClaim hands each record to the message handler, under a context carrying the
+// trace context propagated in the record headers. Failures are reported together
+// with the original record so the error handler can dead-letter it. Once the session is
+// cancelled no new record is started, and a record that failed during shutdown is left
+// uncommitted so that it is redelivered instead of dead-lettered. A record is only
+// committed once the error handler accepted its failure.
+func (h *consumerHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for {
+		select {
+		case <-session.Context().Done():
+			return nil
+		case msg, ok := <-claim.Messages():
+			if !ok {
+				return nil
+			}
+
+			if err := h.messageHandler(messageContext(msg), msg.Topic, msg.Value); err != nil {
+				if session.Context().Err() != nil {
+					return nil
+				}
+
+				if !h.reportError(session.Context(), &port.MessageError{
+					Topic:     msg.Topic,
+					Partition: msg.Partition,
+					Offset:    msg.Offset,
+					Key:       msg.Key,
+					Payload:   msg.Value,
+					Err:       err,
+				}) {
+					return nil
+				}
+			}
+			session.MarkMessage(msg, "")
+		}
+	}
+}
+
+// reportError hands a failed record to the error handler until it is accepted, backing off
+// between attempts. It returns false when the session is cancelled first, the record is then
+// left uncommitted so that it is redelivered.
+func (h *consumerHandler) reportError(ctx context.Context, msgErr *port.MessageError) bool {
+	for attempt := 1; ; attempt++ {
+		msgErr.Attempt = attempt
+		if err := h.errorHandler(ctx, msgErr); err == nil {
+			return true
+		}
+
+		timer := time.NewTimer(errorHandlerBackoff.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
+
+// messageContext returns a context carrying the trace context found in the record headers.
+// It is not derived from the session context, so that a message already being processed
+// is allowed to finish when the session is cancelled on shutdown.
+func messageContext(msg *sarama.ConsumerMessage) context.Context {
+	return otel.GetTextMapPropagator().Extract(context.Background(), headerCarrier(msg.Headers))
+}
+
+// headerCarrier adapts Kafka record headers to a propagation.TextMapCarrier.
+type headerCarrier []*sarama.RecordHeader
+
+var _ propagation.TextMapCarrier = headerCarrier(nil)
+
+func (c headerCarrier) Get(key string) string {
+	for _, header := range c {
+		if header != nil && string(header.Key) == key {
+			return string(header.Value)
+		}
+	}
+	return ""
+}
+
+// Set is a no-op, the consumer never writes headers.
+func (c headerCarrier) Set(key, value string) {}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for _, header := range c {
+		if header != nil {
+			keys = append(keys, string(header.Key))
+		}
+	}
+	return keys
+}
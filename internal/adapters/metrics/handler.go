@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+type handler struct {
+	next    port.Hanlder
+	metrics *metrics
+}
+
+// InstrumentHandler tracks the queue depth and counts template render failures per
+// notification type, for messages from the broker and the notification APIs alike.
+func (m *metrics) InstrumentHandler(next port.Hanlder) port.Hanlder {
+	return &handler{
+		next:    next,
+		metrics: m,
+	}
+}
+
+func (h *handler) Handle(ctx context.Context, msg port.Message) error {
+	h.metrics.queueDepth.Inc()
+	defer h.metrics.queueDepth.Dec()
+
+	err := h.next.Handle(ctx, msg)
+	if errors.Is(err, utils.ErrTemplateRender) {
+		h.metrics.templateRenderErrors.WithLabelValues(msg.Type).Inc()
+	}
+	return err
+}
+
+func (h *handler) HandleError(ctx context.Context, err error) error {
+	return h.next.HandleError(ctx, err)
+}
+
+func (h *handler) Drain(ctx context.Context) error {
+	return h.next.Drain(ctx)
+}
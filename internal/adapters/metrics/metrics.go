@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "notification"
+
+// Send outcomes recorded on the send counters and histograms.
+const (
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+)
+
+type metrics struct {
+	registry *prometheus.Registry
+
+	messagesConsumed     *prometheus.CounterVec
+	queueDepth           prometheus.Gauge
+	templateRenderErrors *prometheus.CounterVec
+
+	sends        *prometheus.CounterVec
+	sendDuration *prometheus.HistogramVec
+
+	rateLimiterWait     *prometheus.HistogramVec
+	rateLimiterWaiting  *prometheus.GaugeVec
+	rateLimiterRejected *prometheus.CounterVec
+}
+
+// New creates the notification pipeline collectors on a dedicated registry, together
+// with the Go runtime and process collectors.
+func New() *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+
+		messagesConsumed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_consumed_total",
+			Help:      "Messages received from the message broker, per topic.",
+		}, []string{"topic"}),
+		queueDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_depth",
+			Help:      "Messages received and not yet fully processed.",
+		}),
+		templateRenderErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "template_render_errors_total",
+			Help:      "Messages that failed because their template could not be rendered, per notification type.",
+		}, []string{"type"}),
+
+		sends: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "sends_total",
+			Help:      "Provider send calls, per channel, provider and outcome.",
+		}, []string{"channel", "provider", "outcome"}),
+		sendDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "send_duration_seconds",
+			Help:      "Latency of provider send calls, per channel, provider and outcome.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"channel", "provider", "outcome"}),
+
+		rateLimiterWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rate_limiter_wait_seconds",
+			Help:      "Time spent waiting for a rate limiter slot, per limiter.",
+			Buckets:   []float64{0.001, 0.01, 0.1, 0.5, 1, 5, 15, 30, 60},
+		}, []string{"limiter"}),
+		rateLimiterWaiting: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "rate_limiter_waiting",
+			Help:      "Sends currently waiting for a rate limiter slot, per limiter.",
+		}, []string{"limiter"}),
+		rateLimiterRejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rate_limiter_rejected_total",
+			Help:      "Requests a rate limiter did not allow immediately, per limiter.",
+		}, []string{"limiter"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.messagesConsumed,
+		m.queueDepth,
+		m.templateRenderErrors,
+		m.sends,
+		m.sendDuration,
+		m.rateLimiterWait,
+		m.rateLimiterWaiting,
+		m.rateLimiterRejected,
+	)
+
+	return m
+}
+
+// Handler serves the collected metrics in the Prometheus exposition format.
+func (m *metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+type rateLimiter struct {
+	next    port.RateLimiter
+	name    string
+	metrics *metrics
+}
+
+// InstrumentRateLimiter records wait time, waiting sends and rejections of the limiter.
+func (m *metrics) InstrumentRateLimiter(name string, next port.RateLimiter) port.RateLimiter {
+	return &rateLimiter{next: next, name: name, metrics: m}
+}
+
+func (r *rateLimiter) Allow() bool {
+	allowed := r.next.Allow()
+	if !allowed {
+		r.metrics.rateLimiterRejected.WithLabelValues(r.name).Inc()
+	}
+	return allowed
+}
+
+func (r *rateLimiter) WaitUntilAllowed(ctx context.Context) error {
+	waiting := r.metrics.rateLimiterWaiting.WithLabelValues(r.name)
+	waiting.Inc()
+	defer waiting.Dec()
+
+	start := time.Now()
+	err := r.next.WaitUntilAllowed(ctx)
+	r.metrics.rateLimiterWait.WithLabelValues(r.name).Observe(time.Since(start).Seconds())
+	return err
+}
+
+type keyedRateLimiter struct {
+	next    port.KeyedRateLimiter
+	name    string
+	metrics *metrics
+}
+
+// InstrumentKeyedRateLimiter records wait time, waiting sends and rejections of the keyed
+// limiter. Keys are not used as labels, they are unbounded.
+func (m *metrics) InstrumentKeyedRateLimiter(name string, next port.KeyedRateLimiter) port.KeyedRateLimiter {
+	return &keyedRateLimiter{next: next, name: name, metrics: m}
+}
+
+func (r *keyedRateLimiter) AllowKey(key string) bool {
+	allowed := r.next.AllowKey(key)
+	if !allowed {
+		r.metrics.rateLimiterRejected.WithLabelValues(r.name).Inc()
+	}
+	return allowed
+}
+
+func (r *keyedRateLimiter) WaitUntilAllowedKey(ctx context.Context, key string) error {
+	waiting := r.metrics.rateLimiterWaiting.WithLabelValues(r.name)
+	waiting.Inc()
+	defer waiting.Dec()
+
+	start := time.Now()
+	err := r.next.WaitUntilAllowedKey(ctx, key)
+	r.metrics.rateLimiterWait.WithLabelValues(r.name).Observe(time.Since(start).Seconds())
+	return err
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+type messageReceiver struct {
+	next    port.MessageReceiver
+	metrics *metrics
+}
+
+// InstrumentMessageReceiver counts the messages consumed per topic, including the records
+// that could not be decoded into a message.
+func (m *metrics) InstrumentMessageReceiver(next port.MessageReceiver) port.MessageReceiver {
+	return &messageReceiver{next: next, metrics: m}
+}
+
+func (r *messageReceiver) Register(topic string, handler func(ctx context.Context, msg port.Message) error) error {
+	return r.next.Register(topic, func(ctx context.Context, msg port.Message) error {
+		r.metrics.messagesConsumed.WithLabelValues(topic).Inc()
+		return handler(ctx, msg)
+	})
+}
+
+func (r *messageReceiver) Listen(ctx context.Context, errorHandler func(ctx context.Context, err error) error, readyHandler func(ready bool)) error {
+	return r.next.Listen(ctx, func(ctx context.Context, err error) error {
+		// Undecodable records never reach a registered handler, count them once
+		var msgErr *port.MessageError
+		if errors.As(err, &msgErr) && msgErr.Attempt <= 1 && errors.Is(msgErr.Err, utils.ErrInvalidMessage) {
+			r.metrics.messagesConsumed.WithLabelValues(msgErr.Topic).Inc()
+		}
+		return errorHandler(ctx, err)
+	}, readyHandler)
+}
+
+func (r *messageReceiver) Close() error {
+	return r.next.Close()
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+type emailer struct {
+	next     port.Emailer
+	provider string
+	metrics  *metrics
+}
+
+type smsSender struct {
+	next     port.SMSSender
+	provider string
+	metrics  *metrics
+}
+
+// InstrumentEmailer records count and latency of every send made through the provider.
+func (m *metrics) InstrumentEmailer(provider string, next port.Emailer) port.Emailer {
+	return &emailer{next: next, provider: provider, metrics: m}
+}
+
+// InstrumentSMSSender records count and latency of every send made through the provider.
+func (m *metrics) InstrumentSMSSender(provider string, next port.SMSSender) port.SMSSender {
+	return &smsSender{next: next, provider: provider, metrics: m}
+}
+
+func (e *emailer) SendEmail(ctx context.Context, to, subject, htmlBody, textBody string) error {
+	start := time.Now()
+	err := e.next.SendEmail(ctx, to, subject, htmlBody, textBody)
+	e.metrics.observeSend("email", e.provider, start, err)
+	return err
+}
+
+// Close closes the instrumented emailer when it holds a connection.
+func (e *emailer) Close() error {
+	if closer, ok := e.next.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (s *smsSender) SendSMS(ctx context.Context, to, message string) error {
+	start := time.Now()
+	err := s.next.SendSMS(ctx, to, message)
+	s.metrics.observeSend("sms", s.provider, start, err)
+	return err
+}
+
+func (m *metrics) observeSend(channel, provider string, start time.Time, err error) {
+	outcome := outcomeSuccess
+	if err != nil {
+		outcome = outcomeError
+	}
+
+	m.sends.WithLabelValues(channel, provider, outcome).Inc()
+	m.sendDuration.WithLabelValues(channel, provider, outcome).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,351 @@
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	googleGrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	notificationv1 "github.com/loganrk/worker-engine/api/notification/v1"
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// batchConcurrency bounds how many notifications of a batch are processed at once.
+const batchConcurrency = 8
+
+type Config struct {
+	Port         int
+	Token        string        // bearer token every call must carry in its authorization metadata
+	Timeout      time.Duration // deadline applied to calls that arrive without one
+	StatusTTL    time.Duration // how long GetStatus reports a notification
+	MaxBatchSize int           // upper bound for the notifications of one SendBatch call
+	TLS          *tls.Config   // optional, calls are served in plaintext without it
+}
+
+type server struct {
+	notificationv1.UnimplementedNotificationServiceServer
+
+	conf        Config
+	logger      port.Logger
+	statusStore port.StatusStore
+	dispatch    func(ctx context.Context, msg port.Message) error // routes a message to the handler for its type
+	grpcServer  *googleGrpc.Server
+}
+
+// New creates the gRPC notification service. Messages are passed to dispatch, which runs
+// them through the same handler as the Kafka consumer, and their states are recorded in
+// statusStore for GetStatus.
+func New(conf Config, loggerIns port.Logger, statusStoreIns port.StatusStore, dispatch func(ctx context.Context, msg port.Message) error) *server {
+	if conf.MaxBatchSize <= 0 {
+		conf.MaxBatchSize = 100
+	}
+
+	s := &server{
+		conf:        conf,
+		logger:      loggerIns,
+		statusStore: statusStoreIns,
+		dispatch:    dispatch,
+	}
+	opts := []googleGrpc.ServerOption{googleGrpc.UnaryInterceptor(s.intercept)}
+	if conf.TLS != nil {
+		opts = append(opts, googleGrpc.Creds(credentials.NewTLS(conf.TLS)))
+	}
+	s.grpcServer = googleGrpc.NewServer(opts...)
+	notificationv1.RegisterNotificationServiceServer(s.grpcServer, s)
+
+	return s
+}
+
+// Start binds the port and serves calls in the background.
+func (s *server) Start(errorHandler func(error)) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.conf.Port))
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		if err := s.grpcServer.Serve(listener); err != nil && !errors.Is(err, googleGrpc.ErrServerStopped) {
+			errorHandler(err)
+		}
+	}()
+	return nil
+}
+
+// Shutdown stops accepting calls and waits for active ones until ctx is done, then
+// cancels the remaining calls.
+func (s *server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		return ctx.Err()
+	}
+}
+
+// intercept authenticates every call and applies the default deadline to calls without one.
+func (s *server) intercept(ctx context.Context, req any, info *googleGrpc.UnaryServerInfo, handler googleGrpc.UnaryHandler) (any, error) {
+	if !s.authorized(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+
+	if _, ok := ctx.Deadline(); !ok && s.conf.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.conf.Timeout)
+		defer cancel()
+	}
+
+	return handler(ctx, req)
+}
+
+// authorized reports whether the call metadata carries the configured bearer token.
+func (s *server) authorized(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || s.conf.Token == "" {
+		return false
+	}
+
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.conf.Token)) == 1
+}
+
+// Send renders and sends one notification before returning.
+func (s *server) Send(ctx context.Context, req *notificationv1.SendRequest) (*notificationv1.SendResponse, error) {
+	if violations := validate(req.GetNotification()); len(violations) > 0 {
+		return nil, invalidArgument(violations)
+	}
+
+	id, err := s.send(ctx, req.GetNotification())
+	if err != nil {
+		return nil, errorStatus(id, err)
+	}
+
+	return &notificationv1.SendResponse{Id: id, Status: notificationv1.Status_STATUS_SENT}, nil
+}
+
+// SendBatch sends the notifications concurrently within the call deadline. Failures are
+// reported per notification, only an invalid batch fails the call.
+func (s *server) SendBatch(ctx context.Context, req *notificationv1.SendBatchRequest) (*notificationv1.SendBatchResponse, error) {
+	notifications := req.GetNotifications()
+	if len(notifications) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "notifications must not be empty")
+	}
+	if len(notifications) > s.conf.MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch of %d notifications exceeds the limit of %d", len(notifications), s.conf.MaxBatchSize)
+	}
+
+	results := make([]*notificationv1.SendResponse, len(notifications))
+	sem := make(chan struct{}, batchConcurrency)
+	var wg sync.WaitGroup
+
+	for i, notification := range notifications {
+		if violations := validate(notification); len(violations) > 0 {
+			results[i] = &notificationv1.SendResponse{
+				Id:     notification.GetIdempotencyKey(),
+				Status: notificationv1.Status_STATUS_REJECTED,
+				Error:  violationsMessage(violations),
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, notification *notificationv1.Notification) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			id, err := s.send(ctx, notification)
+			results[i] = &notificationv1.SendResponse{Id: id, Status: toProtoState(stateOf(err))}
+			if err != nil {
+				results[i].Error = err.Error()
+			}
+		}(i, notification)
+	}
+	wg.Wait()
+
+	return &notificationv1.SendBatchResponse{Results: results}, nil
+}
+
+// GetStatus returns the last recorded state of a notification.
+func (s *server) GetStatus(ctx context.Context, req *notificationv1.GetStatusRequest) (*notificationv1.GetStatusResponse, error) {
+	if req.GetId() == "" {
+		return nil, invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: "id", Description: "is required"}})
+	}
+
+	notificationStatus, ok, err := s.statusStore.GetStatus(ctx, req.GetId())
+	if err != nil {
+		s.logger.Errorw(ctx, "Failed to read notification status", "id", req.GetId(), "error", err)
+		return nil, status.Error(codes.Unavailable, "failed to read notification status")
+	}
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "notification %s not found", req.GetId())
+	}
+
+	return &notificationv1.GetStatusResponse{
+		Id:        notificationStatus.ID,
+		Status:    toProtoState(notificationStatus.State),
+		Error:     notificationStatus.Error,
+		UpdatedAt: timestamppb.New(notificationStatus.UpdatedAt),
+	}, nil
+}
+
+// send dispatches a validated notification and records its states. It returns the
+// notification ID, the idempotency key when one is supplied.
+func (s *server) send(ctx context.Context, notification *notificationv1.Notification) (string, error) {
+	id := notification.GetIdempotencyKey()
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	s.setStatus(ctx, id, port.NOTIFICATION_PENDING, nil)
+
+	err := s.dispatch(ctx, port.Message{
+		IdempotencyKey: id,
+		Type:           notification.GetType(),
+		To:             notification.GetTo(),
+		Subject:        notification.GetSubject(),
+		Locale:         notification.GetLocale(),
+		Macros:         notification.GetMacros(),
+	})
+	if err != nil {
+		s.logger.Errorw(ctx, "Failed to process gRPC notification", "id", id, "type", notification.GetType(), "to", notification.GetTo(), "error", err)
+	}
+
+	s.setStatus(ctx, id, stateOf(err), err)
+	return id, err
+}
+
+// setStatus records the state of a notification. Store errors are logged, the status is
+// informational and must not fail the send.
+func (s *server) setStatus(ctx context.Context, id, state string, err error) {
+	notificationStatus := port.NotificationStatus{ID: id, State: state, UpdatedAt: time.Now()}
+	if err != nil {
+		notificationStatus.Error = err.Error()
+	}
+
+	// The status is recorded even when the call deadline has passed
+	if storeErr := s.statusStore.SetStatus(context.WithoutCancel(ctx), notificationStatus, s.conf.StatusTTL); storeErr != nil {
+		s.logger.Warnw(ctx, "Failed to record notification status", "id", id, "error", storeErr)
+	}
+}
+
+// validate checks the fields every notification type needs. The macros a type requires
+// are checked by its usecase.
+func validate(notification *notificationv1.Notification) []*errdetails.BadRequest_FieldViolation {
+	if notification == nil {
+		return []*errdetails.BadRequest_FieldViolation{{Field: "notification", Description: "is required"}}
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	if notification.GetType() == "" {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "type", Description: "is required"})
+	}
+	if notification.GetTo() == "" {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: "to", Description: "is required"})
+	}
+	return violations
+}
+
+// stateOf returns the notification state a processing error leads to.
+func stateOf(err error) string {
+	switch {
+	case err == nil:
+		return port.NOTIFICATION_SENT
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return port.NOTIFICATION_FAILED
+	case !utils.IsTransient(err):
+		return port.NOTIFICATION_REJECTED
+	default:
+		return port.NOTIFICATION_FAILED
+	}
+}
+
+// errorStatus maps a processing error to the status returned to the client. The
+// notification ID is attached as ErrorInfo metadata so the client can query it later.
+func errorStatus(id string, err error) error {
+	switch {
+	case errors.Is(err, utils.ErrUnknownType):
+		return invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: "type", Description: err.Error()}})
+	case errors.Is(err, utils.ErrMissingMacro):
+		return invalidArgument([]*errdetails.BadRequest_FieldViolation{{Field: "macros", Description: err.Error()}})
+	}
+
+	code := codes.Unavailable
+	switch {
+	case errors.Is(err, utils.ErrRateLimited):
+		code = codes.ResourceExhausted
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case !utils.IsTransient(err):
+		code = codes.FailedPrecondition
+	}
+
+	st, detailErr := status.New(code, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   strings.ToUpper(stateOf(err)),
+		Domain:   "worker-engine",
+		Metadata: map[string]string{"id": id},
+	})
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
+
+func invalidArgument(violations []*errdetails.BadRequest_FieldViolation) error {
+	st, err := status.New(codes.InvalidArgument, "invalid notification: "+violationsMessage(violations)).
+		WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, "invalid notification: "+violationsMessage(violations))
+	}
+	return st.Err()
+}
+
+func violationsMessage(violations []*errdetails.BadRequest_FieldViolation) string {
+	messages := make([]string, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.GetField() + " " + violation.GetDescription()
+	}
+	return strings.Join(messages, ", ")
+}
+
+func toProtoState(state string) notificationv1.Status {
+	switch state {
+	case port.NOTIFICATION_PENDING:
+		return notificationv1.Status_STATUS_PENDING
+	case port.NOTIFICATION_SENT:
+		return notificationv1.Status_STATUS_SENT
+	case port.NOTIFICATION_REJECTED:
+		return notificationv1.Status_STATUS_REJECTED
+	case port.NOTIFICATION_FAILED:
+		return notificationv1.Status_STATUS_FAILED
+	default:
+		return notificationv1.Status_STATUS_UNSPECIFIED
+	}
+}
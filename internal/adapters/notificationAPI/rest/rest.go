@@ -0,0 +1,179 @@
+package rest
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// NotificationsPath is the route notifications are posted to.
+const NotificationsPath = "/v1/notifications"
+
+// maxBodySize bounds the request body, a notification is a handful of short fields.
+const maxBodySize = 1 << 20
+
+const (
+	STATUS_SENT     = "sent"
+	STATUS_REJECTED = "rejected"
+	STATUS_FAILED   = "failed"
+)
+
+type Config struct {
+	Token   string        // bearer token every request must carry
+	Timeout time.Duration // upper bound for rendering and sending one notification
+}
+
+type api struct {
+	conf     Config
+	logger   port.Logger
+	dispatch func(ctx context.Context, msg port.Message) error // routes a message to the handler for its type
+}
+
+// notificationRequest carries the same fields as a Kafka message.
+type notificationRequest struct {
+	IdempotencyKey string            `json:"idempotencyKey"`
+	Type           string            `json:"type"`
+	To             string            `json:"to"`
+	Subject        string            `json:"subject"`
+	Locale         string            `json:"locale"`
+	Macros         map[string]string `json:"macros"`
+}
+
+type notificationResponse struct {
+	ID     string       `json:"id,omitempty"`
+	Status string       `json:"status,omitempty"`
+	Error  string       `json:"error,omitempty"`
+	Errors []fieldError `json:"errors,omitempty"`
+}
+
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New creates the notification API. Messages are passed to dispatch, which runs them through
+// the same handler as the Kafka consumer.
+func New(conf Config, loggerIns port.Logger, dispatch func(ctx context.Context, msg port.Message) error) *api {
+	return &api{
+		conf:     conf,
+		logger:   loggerIns,
+		dispatch: dispatch,
+	}
+}
+
+// ServeHTTP handles POST /v1/notifications. The notification is rendered and sent before
+// the response is written, so validation and delivery errors are returned synchronously.
+func (a *api) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeJSON(w, http.StatusMethodNotAllowed, notificationResponse{Error: "method not allowed"})
+		return
+	}
+
+	if !a.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSON(w, http.StatusUnauthorized, notificationResponse{Error: "missing or invalid bearer token"})
+		return
+	}
+
+	var req notificationRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, notificationResponse{Error: "invalid request body: " + err.Error()})
+		return
+	}
+
+	if errs := req.validate(); len(errs) > 0 {
+		writeJSON(w, http.StatusUnprocessableEntity, notificationResponse{Status: STATUS_REJECTED, Error: "invalid notification", Errors: errs})
+		return
+	}
+
+	// A key supplied by the client makes retries of the same request idempotent
+	id := r.Header.Get("Idempotency-Key")
+	if id == "" {
+		id = req.IdempotencyKey
+	}
+	if id == "" {
+		id = uuid.NewString()
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), a.conf.Timeout)
+	defer cancel()
+
+	err := a.dispatch(ctx, port.Message{
+		IdempotencyKey: id,
+		Type:           req.Type,
+		To:             req.To,
+		Subject:        req.Subject,
+		Locale:         req.Locale,
+		Macros:         req.Macros,
+	})
+	if err != nil {
+		a.logger.Errorw(ctx, "Failed to process API notification", "id", id, "type", req.Type, "to", req.To, "error", err)
+		status, resp := errorResponse(err)
+		resp.ID = id
+		writeJSON(w, status, resp)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, notificationResponse{ID: id, Status: STATUS_SENT})
+}
+
+// authorized reports whether the request carries the configured bearer token.
+func (a *api) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || a.conf.Token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(a.conf.Token)) == 1
+}
+
+// validate checks the fields every notification type needs. The macros a type requires
+// are checked by its usecase.
+func (req notificationRequest) validate() []fieldError {
+	var errs []fieldError
+	if req.Type == "" {
+		errs = append(errs, fieldError{Field: "type", Message: "is required"})
+	}
+	if req.To == "" {
+		errs = append(errs, fieldError{Field: "to", Message: "is required"})
+	}
+	return errs
+}
+
+// errorResponse maps a processing error to the HTTP status and body returned to the client.
+func errorResponse(err error) (int, notificationResponse) {
+	switch {
+	case errors.Is(err, utils.ErrUnknownType):
+		return http.StatusUnprocessableEntity, notificationResponse{Status: STATUS_REJECTED, Error: "invalid notification", Errors: []fieldError{{Field: "type", Message: err.Error()}}}
+	case errors.Is(err, utils.ErrMissingMacro):
+		return http.StatusUnprocessableEntity, notificationResponse{Status: STATUS_REJECTED, Error: "invalid notification", Errors: []fieldError{{Field: "macros", Message: err.Error()}}}
+	case errors.Is(err, utils.ErrRateLimited):
+		return http.StatusTooManyRequests, notificationResponse{Status: STATUS_REJECTED, Error: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, notificationResponse{Status: STATUS_FAILED, Error: err.Error()}
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable, notificationResponse{Status: STATUS_FAILED, Error: err.Error()}
+	case !utils.IsTransient(err):
+		// Permanent failures, such as a recipient the provider rejects, fail again on retry
+		return http.StatusUnprocessableEntity, notificationResponse{Status: STATUS_REJECTED, Error: err.Error()}
+	default:
+		return http.StatusServiceUnavailable, notificationResponse{Status: STATUS_FAILED, Error: err.Error()}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body notificationResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
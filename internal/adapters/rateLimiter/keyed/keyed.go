@@ -0,0 +1,88 @@
+package keyed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is the limit applied to a single key.
+type Limiter interface {
+	// Reserve takes a slot at now when one is left, otherwise it returns how long to wait.
+	Reserve(now time.Time) (bool, time.Duration)
+	// Idle reports whether the limit has fully recovered at now.
+	Idle(now time.Time) bool
+}
+
+// keyedLimiter applies a separate limiter to every key, such as a recipient address.
+type keyedLimiter struct {
+	mu            sync.Mutex
+	newLimiter    func() Limiter
+	sweepInterval time.Duration
+	keys          map[string]Limiter
+	lastSweep     time.Time
+}
+
+// New initializes a keyed limiter. The limiter of a key is created by newLimiter on first
+// use and dropped once idle, idle keys are looked for at most once per sweepInterval.
+func New(newLimiter func() Limiter, sweepInterval time.Duration) *keyedLimiter {
+	return &keyedLimiter{
+		newLimiter:    newLimiter,
+		sweepInterval: sweepInterval,
+		keys:          make(map[string]Limiter),
+		lastSweep:     time.Now(),
+	}
+}
+
+// AllowKey returns true if the request for key can be served immediately.
+func (k *keyedLimiter) AllowKey(key string) bool {
+	allowed, _ := k.reserve(key, time.Now())
+	return allowed
+}
+
+// WaitUntilAllowedKey blocks until the request for key is allowed or context is cancelled.
+func (k *keyedLimiter) WaitUntilAllowedKey(ctx context.Context, key string) error {
+	for {
+		allowed, wait := k.reserve(key, time.Now())
+		if allowed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+			// Retry after wait
+		}
+	}
+}
+
+// reserve takes a slot of the limiter of key. The lock is held throughout, so that a limiter
+// is never swept while a request is being counted on it.
+func (k *keyedLimiter) reserve(key string, now time.Time) (bool, time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.sweep(now)
+
+	l, ok := k.keys[key]
+	if !ok {
+		l = k.newLimiter()
+		k.keys[key] = l
+	}
+	return l.Reserve(now)
+}
+
+// sweep removes the limiters of idle keys, at most once per sweep interval.
+func (k *keyedLimiter) sweep(now time.Time) {
+	if now.Sub(k.lastSweep) < k.sweepInterval {
+		return
+	}
+
+	for key, l := range k.keys {
+		if l.Idle(now) {
+			delete(k.keys, key)
+		}
+	}
+	k.lastSweep = now
+}
@@ -0,0 +1,14 @@
+package leakyBucket
+
+import (
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/adapters/rateLimiter/keyed"
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// NewKeyed initializes a limiter applying a separate leaky bucket to every key, such as a
+// recipient address. Keys are created on first use and dropped once their bucket is full.
+func NewKeyed(capacity int, interval time.Duration) port.KeyedRateLimiter {
+	return keyed.New(func() keyed.Limiter { return New(capacity, interval) }, interval)
+}
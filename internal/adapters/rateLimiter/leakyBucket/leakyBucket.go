@@ -70,6 +70,34 @@ func (l *limiter) WaitUntilAllowed(ctx context.Context) error {
 	}
 }
 
+// Reserve takes a token at now when one is left. Otherwise it returns how long until the
+// next token leaks back.
+func (l *limiter) Reserve(now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	if l.tokens > 0 {
+		l.tokens--
+		return true, 0
+	}
+
+	wait := l.leakSpacing - now.Sub(l.lastLeak)
+	if wait < 0 {
+		wait = l.leakSpacing
+	}
+	return false, wait
+}
+
+// Idle reports whether the bucket is full again at now.
+func (l *limiter) Idle(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(now)
+	return l.tokens >= l.capacity
+}
+
 // refill adds tokens based on time elapsed since last leak.
 func (l *limiter) refill(now time.Time) {
 	elapsed := now.Sub(l.lastLeak)
@@ -0,0 +1,287 @@
+package redis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	goRedis "github.com/redis/go-redis/v9"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// slidingWindowScript keeps the events of the window in a sorted set scored by their time in
+// microseconds. It records an event and returns 0 when the window has room, otherwise it
+// returns the microseconds until the oldest event leaves the window. The time is read from
+// the server, so replicas with skewed clocks share one window.
+// KEYS[1] window key, ARGV[1] window in microseconds, ARGV[2] max events, ARGV[3] event id
+var slidingWindowScript = goRedis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local window = tonumber(ARGV[1])
+local maxEvents = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+if redis.call('ZCARD', KEYS[1]) < maxEvents then
+	redis.call('ZADD', KEYS[1], now, now .. '-' .. ARGV[3])
+	redis.call('PEXPIRE', KEYS[1], math.ceil(window / 1000))
+	return 0
+end
+
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return math.max(1, tonumber(oldest[2]) + window - now)
+`)
+
+// gcraScript is the generic cell rate algorithm, the leaky bucket kept as the theoretical
+// arrival time of the next request in microseconds. It returns 0 when the request conforms,
+// otherwise the microseconds until it would.
+// KEYS[1] bucket key, ARGV[1] emission interval in microseconds, ARGV[2] capacity
+var gcraScript = goRedis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local interval = tonumber(ARGV[1])
+local capacity = tonumber(ARGV[2])
+
+local tat = tonumber(redis.call('GET', KEYS[1]) or now)
+local newTat = math.max(tat, now) + interval
+local allowAt = newTat - capacity * interval
+if now < allowAt then
+	return math.max(1, allowAt - now)
+end
+
+redis.call('SET', KEYS[1], newTat, 'PX', math.ceil((newTat - now) / 1000))
+return 0
+`)
+
+type Config struct {
+	Address       string
+	Password      string
+	DB            int
+	KeyPrefix     string        // prepended to the key of every limiter
+	Timeout       time.Duration // upper bound for one call to the store
+	RetryInterval time.Duration // how long the local limiters are used after the store failed
+}
+
+// store shares rate limits across worker replicas through a server speaking the Redis protocol.
+type store struct {
+	conf   Config
+	logger port.Logger
+	client *goRedis.Client
+
+	mu               sync.Mutex
+	unavailableUntil time.Time // the local limiters are used until then
+}
+
+// New creates the rate limit store. The connection is established on first use, so an
+// unreachable server at startup only means the local limiters are used.
+func New(conf Config, loggerIns port.Logger) *store {
+	return &store{
+		conf:   conf,
+		logger: loggerIns,
+		client: goRedis.NewClient(&goRedis.Options{
+			Addr:         conf.Address,
+			Password:     conf.Password,
+			DB:           conf.DB,
+			DialTimeout:  conf.Timeout,
+			ReadTimeout:  conf.Timeout,
+			WriteTimeout: conf.Timeout,
+			MaxRetries:   -1, // a failed call falls back to the local limiter instead
+		}),
+	}
+}
+
+// SlidingWindow returns a sliding window limiter shared by every replica using the same
+// name. While the store is unreachable, fallback applies the limit within this replica.
+func (s *store) SlidingWindow(name string, maxEvents int, window time.Duration, fallback port.RateLimiter) port.RateLimiter {
+	return &limiter{
+		shared:   s.slidingWindow(name, maxEvents, window),
+		fallback: fallback,
+	}
+}
+
+// KeyedSlidingWindow is SlidingWindow with a separate window for every key.
+func (s *store) KeyedSlidingWindow(name string, maxEvents int, window time.Duration, fallback port.KeyedRateLimiter) port.KeyedRateLimiter {
+	return &keyedLimiter{
+		shared:   s.slidingWindow(name, maxEvents, window),
+		fallback: fallback,
+	}
+}
+
+// KeyedLeakyBucket returns a leaky bucket limiter with a separate bucket for every key,
+// shared by every replica using the same name. While the store is unreachable, fallback
+// applies the limit within this replica.
+func (s *store) KeyedLeakyBucket(name string, capacity int, interval time.Duration, fallback port.KeyedRateLimiter) port.KeyedRateLimiter {
+	return &keyedLimiter{
+		shared: &shared{
+			store:  s,
+			key:    s.conf.KeyPrefix + ":" + name,
+			script: gcraScript,
+			args:   []any{max(interval.Microseconds()/int64(capacity), 1), capacity},
+		},
+		fallback: fallback,
+	}
+}
+
+// Close closes the connections to the store.
+func (s *store) Close() error {
+	return s.client.Close()
+}
+
+func (s *store) slidingWindow(name string, maxEvents int, window time.Duration) *shared {
+	return &shared{
+		store:  s,
+		key:    s.conf.KeyPrefix + ":" + name,
+		script: slidingWindowScript,
+		args:   []any{window.Microseconds(), maxEvents},
+	}
+}
+
+// available reports whether the store is tried, it is skipped for the retry interval after a failure.
+func (s *store) available() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return time.Now().After(s.unavailableUntil)
+}
+
+// failed switches to the local limiters for the retry interval. The switch is logged once.
+func (s *store) failed(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(s.unavailableUntil) {
+		return
+	}
+	s.unavailableUntil = now.Add(s.conf.RetryInterval)
+	s.logger.Warnw(context.Background(), "Rate limit store unreachable, falling back to local limiters", "retryIn", s.conf.RetryInterval, "error", err)
+}
+
+// recovered logs the first successful call after a failure.
+func (s *store) recovered() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.unavailableUntil.IsZero() {
+		return
+	}
+	s.unavailableUntil = time.Time{}
+	s.logger.Infow(context.Background(), "Rate limit store reachable again, using shared limits")
+}
+
+// shared is a limit kept in the store under key.
+type shared struct {
+	store  *store
+	key    string
+	script *goRedis.Script
+	args   []any
+}
+
+// reserve takes a slot of the limit stored under key. It returns how long to wait when there
+// is none, or an error when the store cannot be used.
+func (s *shared) reserve(ctx context.Context, key string) (bool, time.Duration, error) {
+	if !s.store.available() {
+		return false, 0, errUnavailable
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, s.store.conf.Timeout)
+	defer cancel()
+
+	// A unique id per request keeps the members of a sliding window distinct
+	args := append(append([]any{}, s.args...), uuid.NewString())
+
+	wait, err := s.script.Run(callCtx, s.store.client, []string{key}, args...).Int64()
+	if err != nil {
+		// A cancelled caller says nothing about the store
+		if ctx.Err() == nil {
+			s.store.failed(err)
+		}
+		return false, 0, err
+	}
+
+	s.store.recovered()
+	return wait == 0, time.Duration(wait) * time.Microsecond, nil
+}
+
+// wait blocks until a slot of key is taken. It returns errUnavailable as soon as the store
+// cannot be used, so the caller continues on its local limiter.
+func (s *shared) wait(ctx context.Context, key string) error {
+	for {
+		allowed, wait, err := s.reserve(ctx, key)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			return errUnavailable
+		}
+		if allowed {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+			// Retry after wait
+		}
+	}
+}
+
+// errUnavailable is returned by shared while the store cannot be used.
+var errUnavailable = errors.New("rate limit store unavailable")
+
+type limiter struct {
+	shared   *shared
+	fallback port.RateLimiter
+}
+
+// Allow returns true if the request can proceed immediately.
+func (l *limiter) Allow() bool {
+	allowed, _, err := l.shared.reserve(context.Background(), l.shared.key)
+	if err != nil {
+		return l.fallback.Allow()
+	}
+	return allowed
+}
+
+// WaitUntilAllowed blocks until the request is allowed or the context is canceled.
+func (l *limiter) WaitUntilAllowed(ctx context.Context) error {
+	err := l.shared.wait(ctx, l.shared.key)
+	if errors.Is(err, errUnavailable) {
+		return l.fallback.WaitUntilAllowed(ctx)
+	}
+	return err
+}
+
+type keyedLimiter struct {
+	shared   *shared
+	fallback port.KeyedRateLimiter
+}
+
+// AllowKey returns true if the request for key can proceed immediately.
+func (l *keyedLimiter) AllowKey(key string) bool {
+	allowed, _, err := l.shared.reserve(context.Background(), l.storeKey(key))
+	if err != nil {
+		return l.fallback.AllowKey(key)
+	}
+	return allowed
+}
+
+// WaitUntilAllowedKey blocks until the request for key is allowed or the context is canceled.
+func (l *keyedLimiter) WaitUntilAllowedKey(ctx context.Context, key string) error {
+	err := l.shared.wait(ctx, l.storeKey(key))
+	if errors.Is(err, errUnavailable) {
+		return l.fallback.WaitUntilAllowedKey(ctx, key)
+	}
+	return err
+}
+
+// storeKey hashes key, which is a recipient address, so that none is kept in the store.
+func (l *keyedLimiter) storeKey(key string) string {
+	digest := sha256.Sum256([]byte(key))
+	return l.shared.key + ":" + hex.EncodeToString(digest[:16])
+}
@@ -0,0 +1,173 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// nopLogger discards the fallback and recovery logs of the store.
+type nopLogger struct {
+	port.Logger
+}
+
+func (nopLogger) Warnw(ctx context.Context, msg string, keysAndValues ...any) {}
+func (nopLogger) Infow(ctx context.Context, msg string, keysAndValues ...any) {}
+
+// fallbackLimiter records the calls that reached the local limiter.
+type fallbackLimiter struct {
+	calls int
+}
+
+func (f *fallbackLimiter) AllowKey(key string) bool {
+	f.calls++
+	return true
+}
+
+func (f *fallbackLimiter) WaitUntilAllowedKey(ctx context.Context, key string) error {
+	f.calls++
+	return nil
+}
+
+func newStore(t *testing.T, addr string) *store {
+	t.Helper()
+
+	s := New(Config{
+		Address:       addr,
+		KeyPrefix:     "test:ratelimit",
+		Timeout:       100 * time.Millisecond,
+		RetryInterval: 200 * time.Millisecond,
+	}, nopLogger{})
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestKeyedLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		newLimit func(s *store, fallback port.KeyedRateLimiter) port.KeyedRateLimiter
+		wantWait time.Duration
+	}{
+		{
+			name: "sliding window",
+			newLimit: func(s *store, fallback port.KeyedRateLimiter) port.KeyedRateLimiter {
+				return s.KeyedSlidingWindow("recipient", 2, time.Minute, fallback)
+			},
+			wantWait: time.Minute,
+		},
+		{
+			name: "leaky bucket",
+			newLimit: func(s *store, fallback port.KeyedRateLimiter) port.KeyedRateLimiter {
+				return s.KeyedLeakyBucket("recipient", 2, time.Minute, fallback)
+			},
+			wantWait: 30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mr := miniredis.RunT(t)
+			fallback := &fallbackLimiter{}
+
+			// Two replicas, each with its own connection to the server
+			first := tt.newLimit(newStore(t, mr.Addr()), fallback)
+			second := tt.newLimit(newStore(t, mr.Addr()), fallback)
+
+			if !first.AllowKey("user@example.com") {
+				t.Fatal("first request denied")
+			}
+			if !second.AllowKey("user@example.com") {
+				t.Fatal("second request denied")
+			}
+			if first.AllowKey("user@example.com") || second.AllowKey("user@example.com") {
+				t.Fatal("request over the shared limit allowed")
+			}
+			if !second.AllowKey("other@example.com") {
+				t.Fatal("request of another key denied")
+			}
+			if fallback.calls != 0 {
+				t.Fatalf("fallback used %d times while the server is up", fallback.calls)
+			}
+
+			// The recipient is only kept hashed
+			for _, key := range mr.Keys() {
+				if strings.Contains(key, "example.com") {
+					t.Fatalf("key %q contains the recipient", key)
+				}
+			}
+
+			// The wait returned over the limit is the time until a slot frees up
+			keyed := first.(*keyedLimiter)
+			allowed, wait, err := keyed.shared.reserve(context.Background(), keyed.storeKey("user@example.com"))
+			if err != nil {
+				t.Fatalf("reserve: %v", err)
+			}
+			if allowed {
+				t.Fatal("reserve over the limit allowed")
+			}
+			if wait <= tt.wantWait-5*time.Second || wait > tt.wantWait {
+				t.Fatalf("wait = %s, want just under %s", wait, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestWaitUntilAllowed(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := newStore(t, mr.Addr())
+	limit := s.SlidingWindow("channel", 1, 200*time.Millisecond, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := limit.WaitUntilAllowed(ctx); err != nil {
+		t.Fatalf("first wait: %v", err)
+	}
+
+	start := time.Now()
+	if err := limit.WaitUntilAllowed(ctx); err != nil {
+		t.Fatalf("second wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond {
+		t.Fatalf("second request waited %s, want about the window", elapsed)
+	}
+}
+
+func TestFallbackWhileUnavailable(t *testing.T) {
+	mr := miniredis.RunT(t)
+	fallback := &fallbackLimiter{}
+	limit := newStore(t, mr.Addr()).KeyedSlidingWindow("recipient", 1, time.Minute, fallback)
+
+	if !limit.AllowKey("user@example.com") {
+		t.Fatal("first request denied")
+	}
+
+	// The local limiter applies while the server is down, and for the retry interval after
+	mr.Close()
+	if !limit.AllowKey("user@example.com") {
+		t.Fatal("request denied while the server is down")
+	}
+	if err := mr.Restart(); err != nil {
+		t.Fatalf("restart: %v", err)
+	}
+	if err := limit.WaitUntilAllowedKey(context.Background(), "user@example.com"); err != nil {
+		t.Fatalf("wait within the retry interval: %v", err)
+	}
+	if fallback.calls != 2 {
+		t.Fatalf("fallback used %d times, want 2", fallback.calls)
+	}
+
+	// The shared limit applies again once the retry interval has passed
+	time.Sleep(250 * time.Millisecond)
+	if limit.AllowKey("user@example.com") {
+		t.Fatal("request over the shared limit allowed after recovery")
+	}
+	if fallback.calls != 2 {
+		t.Fatalf("fallback used after recovery, %d calls", fallback.calls)
+	}
+}
@@ -0,0 +1,14 @@
+package slidingWindow
+
+import (
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/adapters/rateLimiter/keyed"
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// NewKeyed initializes a limiter applying a separate sliding window to every key, such as a
+// recipient address. Keys are created on first use and dropped once their window is empty.
+func NewKeyed(maxEvents int, window time.Duration) port.KeyedRateLimiter {
+	return keyed.New(func() keyed.Limiter { return New(maxEvents, window) }, window)
+}
@@ -64,6 +64,34 @@ func (l *limiter) WaitUntilAllowed(ctx context.Context) error {
 	}
 }
 
+// Reserve records an event at now when the window has room. Otherwise it returns how long
+// until the oldest event leaves the window.
+func (l *limiter) Reserve(now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cleanupExpired(now)
+	if len(l.timestamps) < l.maxEvents {
+		l.timestamps = append(l.timestamps, now)
+		return true, 0
+	}
+
+	waitDuration := l.timestamps[0].Add(l.window).Sub(now)
+	if waitDuration <= 0 {
+		waitDuration = 100 * time.Millisecond
+	}
+	return false, waitDuration
+}
+
+// Idle reports whether no event of the window remains at now.
+func (l *limiter) Idle(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cleanupExpired(now)
+	return len(l.timestamps) == 0
+}
+
 // cleanupExpired removes timestamps outside the sliding window.
 func (l *limiter) cleanupExpired(now time.Time) {
 	if len(l.timestamps) == 0 {
@@ -0,0 +1,109 @@
+package twilio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// DefaultBaseURL is the public Twilio REST API endpoint.
+const DefaultBaseURL = "https://api.twilio.com"
+
+// maxResponseSize bounds how much of a response body is read.
+const maxResponseSize = 64 << 10
+
+type TwilioSender struct {
+	Client     *http.Client
+	BaseURL    string
+	AccountSID string
+	AuthToken  string
+	From       string
+}
+
+// response is the subset of the Twilio message resource we care about.
+type response struct {
+	SID          string `json:"sid"`
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Code         int    `json:"code"`    // only set on API errors
+	Message      string `json:"message"` // only set on API errors
+}
+
+// New creates a Twilio-compatible SMS sender. An empty baseURL falls back to the
+// public Twilio endpoint, which allows pointing the adapter at a local stand-in.
+func New(baseURL, accountSID, authToken, from string, timeout time.Duration) *TwilioSender {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &TwilioSender{
+		Client:     &http.Client{Timeout: timeout},
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		AccountSID: accountSID,
+		AuthToken:  authToken,
+		From:       from,
+	}
+}
+
+func (t *TwilioSender) SendSMS(ctx context.Context, to, message string) error {
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", t.BaseURL, url.PathEscape(t.AccountSID))
+
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", t.From)
+	form.Set("Body", message)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.AccountSID, t.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return fmt.Errorf("failed to read sms response, status code: %d, error: %w", resp.StatusCode, err)
+	}
+
+	// The status decides the outcome, error pages of proxies in front of the API need not be JSON
+	var result response
+	decodeErr := json.Unmarshal(body, &result)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message := result.Message
+		if decodeErr != nil {
+			message = strings.TrimSpace(string(body))
+		}
+		err := fmt.Errorf("sms sending failed, status code: %d, code: %d, message: %s", resp.StatusCode, result.Code, message)
+
+		// Client errors such as an invalid number will not succeed on retry, throttling and auth errors might
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusUnauthorized && resp.StatusCode != http.StatusTooManyRequests {
+			return utils.Permanent(err)
+		}
+		return err
+	}
+
+	if decodeErr != nil {
+		return fmt.Errorf("failed to decode sms response, status code: %d, error: %w", resp.StatusCode, decodeErr)
+	}
+
+	// Twilio accepts the message asynchronously; anything other than a failed state is a success here
+	if result.Status == "failed" || result.Status == "undelivered" {
+		return utils.Permanent(fmt.Errorf("sms sending failed, status: %s, message: %s", result.Status, result.ErrorMessage))
+	}
+
+	return nil
+}
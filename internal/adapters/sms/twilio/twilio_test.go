@@ -0,0 +1,114 @@
+package twilio
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+func TestSendSMS(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		contentType   string
+		body          string
+		wantErr       bool
+		wantTransient bool
+	}{
+		{
+			name:   "accepted",
+			status: http.StatusCreated,
+			body:   `{"sid":"SM123","status":"queued"}`,
+		},
+		{
+			name:    "failed state",
+			status:  http.StatusCreated,
+			body:    `{"sid":"SM123","status":"failed","error_message":"unreachable"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid number",
+			status:  http.StatusBadRequest,
+			body:    `{"code":21211,"message":"Invalid 'To' Phone Number"}`,
+			wantErr: true,
+		},
+		{
+			name:          "unauthorized",
+			status:        http.StatusUnauthorized,
+			body:          `{"code":20003,"message":"Authenticate"}`,
+			wantErr:       true,
+			wantTransient: true,
+		},
+		{
+			name:          "throttled",
+			status:        http.StatusTooManyRequests,
+			body:          `{"code":20429,"message":"Too Many Requests"}`,
+			wantErr:       true,
+			wantTransient: true,
+		},
+		{
+			name:          "server error",
+			status:        http.StatusServiceUnavailable,
+			body:          `{"code":20500,"message":"Internal Server Error"}`,
+			wantErr:       true,
+			wantTransient: true,
+		},
+		{
+			name:        "client error with html body",
+			status:      http.StatusNotFound,
+			contentType: "text/html",
+			body:        "<html><body>Not Found</body></html>",
+			wantErr:     true,
+		},
+		{
+			name:          "server error with html body",
+			status:        http.StatusBadGateway,
+			contentType:   "text/html",
+			body:          "<html><body>Bad Gateway</body></html>",
+			wantErr:       true,
+			wantTransient: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+					t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+				}
+				if sid, token, ok := r.BasicAuth(); !ok || sid != "AC123" || token != "secret" {
+					t.Errorf("unexpected credentials %q %q", sid, token)
+				}
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("failed to parse form: %v", err)
+				}
+				if r.PostForm.Get("To") != "+15005550006" || r.PostForm.Get("From") != "+15005550001" || r.PostForm.Get("Body") != "hello" {
+					t.Errorf("unexpected form %v", r.PostForm)
+				}
+
+				contentType := tt.contentType
+				if contentType == "" {
+					contentType = "application/json"
+				}
+				w.Header().Set("Content-Type", contentType)
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			sender := New(server.URL+"/", "AC123", "secret", "+15005550001", 5*time.Second)
+			err := sender.SendSMS(context.Background(), "+15005550006", "hello")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SendSMS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && utils.IsTransient(err) != tt.wantTransient {
+				t.Errorf("IsTransient(%v) = %v, want %v", err, utils.IsTransient(err), tt.wantTransient)
+			}
+		})
+	}
+}
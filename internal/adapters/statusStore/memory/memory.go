@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+// sweepInterval bounds how often expired statuses are purged.
+const sweepInterval = time.Minute
+
+type entry struct {
+	status port.NotificationStatus
+	expiry time.Time
+}
+
+type store struct {
+	mu        sync.Mutex
+	entries   map[string]entry // notification ID -> last status
+	lastSweep time.Time
+}
+
+// New creates an in-process notification status store. Statuses are lost on restart.
+func New() *store {
+	return &store{
+		entries:   make(map[string]entry),
+		lastSweep: time.Now(),
+	}
+}
+
+// SetStatus records the status of a notification for the given ttl.
+func (s *store) SetStatus(ctx context.Context, status port.NotificationStatus, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.entries[status.ID] = entry{status: status, expiry: now.Add(ttl)}
+	s.sweep(now)
+
+	return nil
+}
+
+// GetStatus returns the status recorded for id, if it has not expired yet.
+func (s *store) GetStatus(ctx context.Context, id string) (port.NotificationStatus, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[id]
+	if !ok || !time.Now().Before(e.expiry) {
+		return port.NotificationStatus{}, false, nil
+	}
+	return e.status, true, nil
+}
+
+// sweep removes expired statuses, at most once per sweepInterval.
+func (s *store) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+
+	for id, e := range s.entries {
+		if !now.Before(e.expiry) {
+			delete(s.entries, id)
+		}
+	}
+	s.lastSweep = now
+}
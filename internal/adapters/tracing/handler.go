@@ -0,0 +1,43 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+type handler struct {
+	next   port.Hanlder
+	tracer trace.Tracer
+}
+
+// InstrumentHandler creates a consumer span for every handled message, as a child of the
+// trace context carried by ctx.
+func (t *tracer) InstrumentHandler(next port.Hanlder) port.Hanlder {
+	return &handler{next: next, tracer: t.tracer}
+}
+
+func (h *handler) Handle(ctx context.Context, msg port.Message) error {
+	return h.trace(ctx, "Handle", msg, h.next.Handle)
+}
+
+func (h *handler) HandleError(ctx context.Context, err error) error {
+	return h.next.HandleError(ctx, err)
+}
+
+func (h *handler) Drain(ctx context.Context) error {
+	return h.next.Drain(ctx)
+}
+
+func (h *handler) trace(ctx context.Context, name string, msg port.Message, next func(context.Context, port.Message) error) error {
+	ctx, span := h.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(
+		attribute.String("notification.type", msg.Type),
+		attribute.String("notification.idempotency_key", msg.IdempotencyKey),
+	))
+	err := next(ctx, msg)
+	end(span, err)
+	return err
+}
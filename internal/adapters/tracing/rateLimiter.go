@@ -0,0 +1,56 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+type rateLimiter struct {
+	next   port.RateLimiter
+	name   string
+	tracer trace.Tracer
+}
+
+// InstrumentRateLimiter creates a span around every wait for a limiter slot. Allow does
+// not block and is not traced.
+func (t *tracer) InstrumentRateLimiter(name string, next port.RateLimiter) port.RateLimiter {
+	return &rateLimiter{next: next, name: name, tracer: t.tracer}
+}
+
+func (r *rateLimiter) Allow() bool {
+	return r.next.Allow()
+}
+
+func (r *rateLimiter) WaitUntilAllowed(ctx context.Context) error {
+	ctx, span := r.tracer.Start(ctx, "rate_limiter.wait", trace.WithAttributes(attribute.String("rate_limiter.name", r.name)))
+	err := r.next.WaitUntilAllowed(ctx)
+	end(span, err)
+	return err
+}
+
+type keyedRateLimiter struct {
+	next   port.KeyedRateLimiter
+	name   string
+	tracer trace.Tracer
+}
+
+// InstrumentKeyedRateLimiter creates a span around every wait for a slot of a key. The key
+// is a recipient address and is not recorded.
+func (t *tracer) InstrumentKeyedRateLimiter(name string, next port.KeyedRateLimiter) port.KeyedRateLimiter {
+	return &keyedRateLimiter{next: next, name: name, tracer: t.tracer}
+}
+
+func (r *keyedRateLimiter) AllowKey(key string) bool {
+	return r.next.AllowKey(key)
+}
+
+func (r *keyedRateLimiter) WaitUntilAllowedKey(ctx context.Context, key string) error {
+	ctx, span := r.tracer.Start(ctx, "rate_limiter.wait", trace.WithAttributes(attribute.String("rate_limiter.name", r.name)))
+	err := r.next.WaitUntilAllowedKey(ctx, key)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,61 @@
+package tracing
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/loganrk/worker-engine/internal/core/port"
+)
+
+type emailer struct {
+	next     port.Emailer
+	provider string
+	tracer   trace.Tracer
+}
+
+// InstrumentEmailer creates a client span around every provider send.
+func (t *tracer) InstrumentEmailer(provider string, next port.Emailer) port.Emailer {
+	return &emailer{next: next, provider: provider, tracer: t.tracer}
+}
+
+func (e *emailer) SendEmail(ctx context.Context, to, subject, htmlBody, textBody string) error {
+	ctx, span := e.tracer.Start(ctx, "email.send", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("notification.channel", "email"),
+		attribute.String("notification.provider", e.provider),
+	))
+	err := e.next.SendEmail(ctx, to, subject, htmlBody, textBody)
+	end(span, err)
+	return err
+}
+
+// Close closes the instrumented emailer when it holds a connection.
+func (e *emailer) Close() error {
+	if closer, ok := e.next.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+type smsSender struct {
+	next     port.SMSSender
+	provider string
+	tracer   trace.Tracer
+}
+
+// InstrumentSMSSender creates a client span around every provider send.
+func (t *tracer) InstrumentSMSSender(provider string, next port.SMSSender) port.SMSSender {
+	return &smsSender{next: next, provider: provider, tracer: t.tracer}
+}
+
+func (s *smsSender) SendSMS(ctx context.Context, to, message string) error {
+	ctx, span := s.tracer.Start(ctx, "sms.send", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("notification.channel", "sms"),
+		attribute.String("notification.provider", s.provider),
+	))
+	err := s.next.SendSMS(ctx, to, message)
+	end(span, err)
+	return err
+}
@@ -0,0 +1,116 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName identifies the spans created by this service.
+const InstrumentationName = "github.com/loganrk/worker-engine"
+
+const (
+	EXPORTER_OTLP   = "otlp"
+	EXPORTER_STDOUT = "stdout"
+)
+
+// Config holds the settings of the span exporter.
+type Config struct {
+	Enabled     bool    // when false spans are only propagated, never recorded
+	ServiceName string  // reported as service.name
+	Exporter    string  // EXPORTER_OTLP or EXPORTER_STDOUT
+	Endpoint    string  // host:port of the OTLP/HTTP collector
+	Insecure    bool    // plain HTTP instead of HTTPS for the OTLP exporter
+	SampleRatio float64 // fraction of new traces that are sampled
+}
+
+type tracer struct {
+	tracer   trace.Tracer
+	provider *sdktrace.TracerProvider
+}
+
+// New installs the global W3C trace context propagator and, when enabled, a global
+// tracer provider exporting spans to the configured exporter.
+func New(conf Config) (*tracer, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if !conf.Enabled {
+		return &tracer{tracer: otel.Tracer(InstrumentationName)}, nil
+	}
+
+	exporter, err := newExporter(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(conf.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return &tracer{tracer: provider.Tracer(InstrumentationName), provider: provider}, nil
+}
+
+func newExporter(conf Config) (sdktrace.SpanExporter, error) {
+	switch conf.Exporter {
+	case EXPORTER_OTLP, "":
+		opts := []otlptracehttp.Option{}
+		if conf.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(conf.Endpoint))
+		}
+		if conf.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		return otlptracehttp.New(context.Background(), opts...)
+	case EXPORTER_STDOUT:
+		return stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter: %s", conf.Exporter)
+	}
+}
+
+// StartSpan starts an internal span as a child of the span carried by ctx. The returned
+// function records the error, if any, and ends the span.
+func (t *tracer) StartSpan(ctx context.Context, name string, attributes map[string]string) (context.Context, func(err error)) {
+	attrs := make([]attribute.KeyValue, 0, len(attributes))
+	for key, value := range attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, func(err error) { end(span, err) }
+}
+
+// Shutdown flushes the spans still buffered by the exporter.
+func (t *tracer) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+	return t.provider.Shutdown(ctx)
+}
+
+// end records err on the span, if any, and ends it.
+func end(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
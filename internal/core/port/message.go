@@ -0,0 +1,78 @@
+package port
+
+import (
+	"fmt"
+	"time"
+)
+
+// Message is a notification request received from a producer.
+type Message struct {
+	IdempotencyKey string // optional, derived from the content when empty
+	Type           string
+	To             string
+	Subject        string // optional, overrides the subject template
+	Locale         string // optional, e.g. "pt-BR", selects localized templates
+	Macros         map[string]string
+}
+
+// States of a notification received through the notification APIs.
+const (
+	NOTIFICATION_PENDING  = "pending"
+	NOTIFICATION_SENT     = "sent"
+	NOTIFICATION_REJECTED = "rejected" // invalid or refused, retrying does not help
+	NOTIFICATION_FAILED   = "failed"   // transient failure, retrying may succeed
+)
+
+// NotificationStatus is the last known state of a notification, keyed by its ID.
+type NotificationStatus struct {
+	ID        string
+	State     string
+	Error     string // reason of the failure, empty unless rejected or failed
+	UpdatedAt time.Time
+}
+
+// MessageError is passed to the error handler when a consumed message fails processing.
+// It keeps the original record so the failure can be dead-lettered and replayed.
+type MessageError struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       []byte
+	Payload   []byte
+	Err       error
+	Attempt   int // 1 on the first call of the error handler for the record, higher when it is retried
+}
+
+func (e *MessageError) Error() string {
+	return fmt.Sprintf("topic %s partition %d offset %d: %v", e.Topic, e.Partition, e.Offset, e.Err)
+}
+
+func (e *MessageError) Unwrap() error {
+	return e.Err
+}
+
+// DeadLetter is the record published for a message that permanently failed.
+type DeadLetter struct {
+	SourceTopic string    `json:"sourceTopic"`
+	Partition   int32     `json:"partition"`
+	Offset      int64     `json:"offset"`
+	Key         string    `json:"key,omitempty"`
+	Payload     string    `json:"payload"`  // original message value
+	Error       string    `json:"error"`    // reason of the last failure
+	Attempts    int       `json:"attempts"` // number of send attempts made
+	FailedAt    time.Time `json:"failedAt"`
+}
+
+// States reported by the readiness probe.
+const (
+	HEALTH_OK            = "ok"
+	HEALTH_UNAVAILABLE   = "unavailable"   // a component is not initialized yet
+	HEALTH_SHUTTING_DOWN = "shutting down" // no new traffic should be routed here
+)
+
+// HealthStatus is the readiness of the worker together with the state of every component.
+type HealthStatus struct {
+	Ready      bool
+	State      string
+	Components map[string]bool // component name -> initialized
+}
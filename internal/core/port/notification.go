@@ -0,0 +1,13 @@
+package port
+
+import "context"
+
+type SvrList struct {
+	Notification NotificationSvr
+}
+
+type NotificationSvr interface {
+	Send(ctx context.Context, msg Message) error
+
+	ReloadTemplates(ctx context.Context) error
+}
@@ -2,17 +2,14 @@ package port
 
 import (
 	"context"
+	"time"
 )
 
 type Hanlder interface {
-	ActivationEmail(to, subject string, macros map[string]string) error
-	ActivationPhone(to string, macros map[string]string) error
+	Handle(ctx context.Context, msg Message) error
+	HandleError(ctx context.Context, err error) error
 
-	PasswordResetEmail(to, subject string, macros map[string]string) error
-	PasswordResetPhone(to string, macros map[string]string) error
-
-	ActivationError(ctx context.Context, err error)
-	PasswordResetError(ctx context.Context, err error)
+	Drain(ctx context.Context) error
 }
 
 // Cipher defines the interface for encrypting and decrypting strings.
@@ -46,26 +43,86 @@ type Logger interface {
 }
 
 type MessageReceiver interface {
-	RegisterPasswordResetHandlers(
-		passwordResetTopic string,
-		phoneHandler func(to string, macros map[string]string) error,
-		emailHandler func(to, subject string, macros map[string]string) error,
-	) error
-	RegisterActivation(
-		activationTopic string,
-		phoneHandler func(to string, macros map[string]string) error,
-		emailHandler func(to, subject string, macros map[string]string) error,
+	Register(topic string, handler func(ctx context.Context, msg Message) error) error
+	Listen(ctx context.Context, errorHandler func(ctx context.Context, err error) error, readyHandler func(ready bool)) error
+	Close() error
+}
+
+type DeadLetterPublisher interface {
+	PublishDeadLetter(ctx context.Context, msg DeadLetter) error
+}
+
+type DeadLetterReader interface {
+	ReadDeadLetters(ctx context.Context, fn func(DeadLetter) error) error
+}
 
-	) error
-	ListenActivationHResetTopic(ctx context.Context, errorHandler func(ctx context.Context, err error)) error
-	ListenPasswordResetTopic(ctx context.Context, errorHandler func(ctx context.Context, err error)) error
+type DedupStore interface {
+	MarkIfAbsent(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	Unmark(ctx context.Context, key string) error
+}
+
+type StatusStore interface {
+	SetStatus(ctx context.Context, status NotificationStatus, ttl time.Duration) error
+	GetStatus(ctx context.Context, id string) (NotificationStatus, bool, error)
 }
 
 type Emailer interface {
-	SendEmail(to, subject, body string) error
+	SendEmail(ctx context.Context, to, subject, htmlBody, textBody string) error
+}
+
+type SMSSender interface {
+	SendSMS(ctx context.Context, to, message string) error
 }
 
 type RateLimiter interface {
 	Allow() bool
 	WaitUntilAllowed(ctx context.Context) error
 }
+
+type KeyedRateLimiter interface {
+	AllowKey(key string) bool
+	WaitUntilAllowedKey(ctx context.Context, key string) error
+}
+
+type RateLimitStore interface {
+	SlidingWindow(name string, maxEvents int, window time.Duration, fallback RateLimiter) RateLimiter
+	KeyedSlidingWindow(name string, maxEvents int, window time.Duration, fallback KeyedRateLimiter) KeyedRateLimiter
+	KeyedLeakyBucket(name string, capacity int, interval time.Duration, fallback KeyedRateLimiter) KeyedRateLimiter
+	Close() error
+}
+
+type Health interface {
+	SetReady(component string)
+	SetNotReady(component string)
+	SetShuttingDown()
+	Readiness() HealthStatus
+}
+
+type FileWatcher interface {
+	Watch(ctx context.Context, paths []string, onChange func()) error
+	Close() error
+}
+
+type Server interface {
+	Start(errorHandler func(error)) error
+	Shutdown(ctx context.Context) error
+}
+
+type Metrics interface {
+	InstrumentEmailer(provider string, emailer Emailer) Emailer
+	InstrumentSMSSender(provider string, smsSender SMSSender) SMSSender
+	InstrumentRateLimiter(name string, rateLimiter RateLimiter) RateLimiter
+	InstrumentKeyedRateLimiter(name string, rateLimiter KeyedRateLimiter) KeyedRateLimiter
+	InstrumentHandler(handler Hanlder) Hanlder
+	InstrumentMessageReceiver(receiver MessageReceiver) MessageReceiver
+}
+
+type Tracer interface {
+	InstrumentEmailer(provider string, emailer Emailer) Emailer
+	InstrumentSMSSender(provider string, smsSender SMSSender) SMSSender
+	InstrumentRateLimiter(name string, rateLimiter RateLimiter) RateLimiter
+	InstrumentKeyedRateLimiter(name string, rateLimiter KeyedRateLimiter) KeyedRateLimiter
+	InstrumentHandler(handler Hanlder) Hanlder
+	StartSpan(ctx context.Context, name string, attributes map[string]string) (context.Context, func(err error))
+	Shutdown(ctx context.Context) error
+}
@@ -0,0 +1,312 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/loganrk/worker-engine/config"
+	"github.com/loganrk/worker-engine/internal/core/port"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// notificationusecase renders and sends every notification type declared in the registry.
+type notificationusecase struct {
+	logger                port.Logger                 // Logger interface for structured logging
+	tracer                port.Tracer                 // Tracer creating the spans of template rendering
+	conf                  config.Notifications        // Registry and template paths, re-read on every reload
+	templates             atomic.Pointer[templateSet] // Last successfully loaded templates
+	reloadMu              sync.Mutex                  // Serializes template reloads
+	emailer               port.Emailer                // Interface to send emails
+	smsSender             port.SMSSender              // Interface to send SMS messages
+	emailRateLimiter      port.RateLimiter
+	smsRateLimiter        port.RateLimiter
+	typeRateLimiters      map[string]port.RateLimiter      // Optional limits per notification type, keyed by type
+	recipientRateLimiters map[string]port.KeyedRateLimiter // Optional limits per recipient, keyed by type
+	retryPolicy           utils.RetryPolicy                // Retry policy applied to every channel send
+}
+
+// New initializes a new notificationusecase instance by loading and parsing the templates of every
+// registered type and setting dependencies.
+func New(conf config.Notifications, loggerIns port.Logger, tracerIns port.Tracer, emailerIns port.Emailer, emailRateLimitIns port.RateLimiter, smsSenderIns port.SMSSender, smsRateLimitIns port.RateLimiter, typeRateLimitIns map[string]port.RateLimiter, recipientRateLimitIns map[string]port.KeyedRateLimiter) (*notificationusecase, error) {
+	// Read and validate the templates from file
+	templates, err := loadTemplates(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	// SMS types need a sender, which is only configured when some type uses the channel
+	if smsSenderIns == nil {
+		for name, typeTemplates := range templates.types {
+			if typeTemplates.channel == config.CHANNEL_SMS {
+				return nil, fmt.Errorf("notification type %s uses the sms channel but no sms sender is configured", name)
+			}
+		}
+	}
+
+	// Return the fully initialized notificationusecase
+	u := &notificationusecase{
+		logger:                loggerIns,
+		tracer:                tracerIns,
+		conf:                  conf,
+		emailer:               emailerIns,
+		smsSender:             smsSenderIns,
+		emailRateLimiter:      emailRateLimitIns,
+		smsRateLimiter:        smsRateLimitIns,
+		typeRateLimiters:      typeRateLimitIns,
+		recipientRateLimiters: recipientRateLimitIns,
+		retryPolicy: utils.RetryPolicy{
+			MaxAttempts: conf.GetRetryMaxAttempts(),
+			BaseDelay:   conf.GetRetryBaseDelay(),
+			MaxDelay:    conf.GetRetryMaxDelay(),
+			Jitter:      conf.GetRetryJitter(),
+		},
+	}
+	u.templates.Store(templates)
+	loggerIns.Infow(context.Background(), "Loaded templates", "version", templates.version, "types", len(templates.types))
+
+	return u, nil
+}
+
+// ReloadTemplates re-reads the templates from disk and swaps them in atomically. When a
+// template fails to load or validate, the previous version keeps being served.
+func (u *notificationusecase) ReloadTemplates(ctx context.Context) error {
+	u.reloadMu.Lock()
+	defer u.reloadMu.Unlock()
+
+	current := u.templates.Load()
+	templates, err := loadTemplates(u.conf)
+	if err != nil {
+		u.logger.Errorw(ctx, "Failed to reload templates, keeping current version", "version", current.version, "error", err)
+		return err
+	}
+
+	if templates.version == current.version {
+		return nil
+	}
+
+	u.templates.Store(templates)
+	u.logger.Infow(ctx, "Reloaded templates", "version", templates.version, "previousVersion", current.version)
+	return nil
+}
+
+// Send renders the templates of the message type and sends the result over the channel of the type.
+func (u *notificationusecase) Send(ctx context.Context, msg port.Message) error {
+	u.logger.Infow(ctx, "Processing notification", "type", msg.Type, "to", msg.To, "subject", msg.Subject, "locale", msg.Locale, "macros", msg.Macros)
+
+	templates, ok := u.templates.Load().types[msg.Type]
+	if !ok {
+		err := utils.Permanent(fmt.Errorf("%w: %s", utils.ErrUnknownType, msg.Type))
+		u.logger.Errorw(ctx, "Rejected notification", "type", msg.Type, "error", err)
+		return err
+	}
+
+	if err := utils.CheckMacros(msg.Macros, templates.requiredMacros); err != nil {
+		u.logger.Errorw(ctx, "Rejected notification", "type", msg.Type, "error", err)
+		return err
+	}
+
+	if err := u.limitRecipient(ctx, msg, templates); err != nil {
+		u.logger.Warnw(ctx, "Rejected notification over recipient rate limit", "type", msg.Type, "action", templates.recipientLimit, "error", err)
+		return err
+	}
+
+	if templates.channel == config.CHANNEL_SMS {
+		return u.sendSMS(ctx, msg, templates)
+	}
+	return u.sendEmail(ctx, msg, templates)
+}
+
+// limitRecipient applies the recipient rate limit of the type, counted once per notification.
+// Over the limit the message is delayed until the recipient is under it again, or rejected
+// with an error wrapping ErrRateLimited, and also ErrDropped when it must not be dead-lettered.
+// A message still over the limit after the max delay is rejected to be dead-lettered, so that
+// one recipient cannot hold up the other messages of the partition.
+func (u *notificationusecase) limitRecipient(ctx context.Context, msg port.Message, templates *typeTemplates) error {
+	limiter := u.recipientRateLimiters[msg.Type]
+	if limiter == nil {
+		return nil
+	}
+	key := recipientKey(templates.channel, msg.To)
+
+	switch templates.recipientLimit {
+	case config.RATE_LIMIT_ACTION_DELAY:
+		waitCtx, cancel := context.WithTimeout(ctx, templates.recipientDelay)
+		defer cancel()
+
+		if err := limiter.WaitUntilAllowedKey(waitCtx, key); err != nil {
+			if ctx.Err() == nil {
+				return utils.Permanent(fmt.Errorf("%w for %s after waiting %s", utils.ErrRateLimited, msg.Type, templates.recipientDelay))
+			}
+			return fmt.Errorf("rate limit error: %w", err)
+		}
+		return nil
+	case config.RATE_LIMIT_ACTION_DEAD_LETTER:
+		if !limiter.AllowKey(key) {
+			return utils.Permanent(fmt.Errorf("%w for %s", utils.ErrRateLimited, msg.Type))
+		}
+		return nil
+	default:
+		if !limiter.AllowKey(key) {
+			return utils.Permanent(fmt.Errorf("%w for %s, %w", utils.ErrRateLimited, msg.Type, utils.ErrDropped))
+		}
+		return nil
+	}
+}
+
+// recipientKey normalizes a recipient, so that spellings of the same address or phone number
+// share their limit.
+func recipientKey(channel, to string) string {
+	if channel != config.CHANNEL_SMS {
+		return strings.ToLower(strings.TrimSpace(to))
+	}
+
+	// Keep the digits and a leading plus of a phone number
+	var key strings.Builder
+	for i, r := range strings.TrimSpace(to) {
+		if (r >= '0' && r <= '9') || (r == '+' && i == 0) {
+			key.WriteRune(r)
+		}
+	}
+	return key.String()
+}
+
+func (u *notificationusecase) sendEmail(ctx context.Context, msg port.Message, templates *typeTemplates) error {
+	htmlBody, textBody, err := u.renderEmail(ctx, msg.Type, templates.body, templates.text, templates.inlineCSS, msg.Locale, msg.Macros)
+	if err != nil {
+		u.logger.Errorw(ctx, "Failed to render email", "type", msg.Type, "error", err)
+		return err
+	}
+
+	subject, err := u.renderSubject(ctx, msg.Type+" subject", msg.Subject, templates.subject, msg.Locale, msg.Macros)
+	if err != nil {
+		u.logger.Errorw(ctx, "Failed to render email subject", "type", msg.Type, "error", err)
+		return err
+	}
+
+	err = u.send(ctx, msg.Type, u.emailRateLimiter, func() error {
+		return u.emailer.SendEmail(ctx, msg.To, subject, htmlBody, textBody)
+	})
+	if err != nil {
+		u.logger.Errorw(ctx, "Failed to send email", "type", msg.Type, "error", err)
+		return err
+	}
+	return nil
+}
+
+func (u *notificationusecase) sendSMS(ctx context.Context, msg port.Message, templates *typeTemplates) error {
+	message, err := u.render(ctx, msg.Type, templates.body, msg.Locale, msg.Macros)
+	if err != nil {
+		u.logger.Errorw(ctx, "Failed to render SMS", "type", msg.Type, "error", err)
+		return err
+	}
+
+	err = u.send(ctx, msg.Type, u.smsRateLimiter, func() error {
+		return u.smsSender.SendSMS(ctx, msg.To, message)
+	})
+	if err != nil {
+		u.logger.Errorw(ctx, "Failed to send SMS", "type", msg.Type, "error", err)
+		return err
+	}
+	return nil
+}
+
+// renderSubject returns the subject supplied by the producer, which overrides the subject
+// templates, or renders the subject template resolved for locale on a single line.
+func (u *notificationusecase) renderSubject(ctx context.Context, name, subject string, templates localized, locale string, macros map[string]string) (string, error) {
+	if subject != "" {
+		return subject, nil
+	}
+
+	subject, err := u.render(ctx, name, templates, locale, macros)
+	if err != nil {
+		return "", err
+	}
+
+	subject = strings.Join(strings.Fields(subject), " ")
+	if subject == "" {
+		return "", utils.Permanent(errors.New("no subject supplied and no subject template configured"))
+	}
+	return subject, nil
+}
+
+// renderEmail renders the HTML body for locale and the plain-text body in the same locale.
+// When there is no text template for that locale, the HTML body is converted to text.
+// The CSS of the HTML body is inlined when enabled for the type or globally.
+func (u *notificationusecase) renderEmail(ctx context.Context, name string, htmlTemplates, textTemplates localized, inlineCSS bool, locale string, macros map[string]string) (string, string, error) {
+	htmlTpl, resolved := htmlTemplates.resolve(locale)
+	htmlBody, err := u.renderTemplate(ctx, name, htmlTpl, resolved, macros)
+	if err != nil {
+		return "", "", err
+	}
+
+	if inlineCSS {
+		if htmlBody, err = utils.InlineCSS(htmlBody); err != nil {
+			return "", "", fmt.Errorf("%w: %w", utils.ErrTemplateRender, err)
+		}
+	}
+
+	textTpl := textTemplates.exact(resolved)
+	if textTpl == nil {
+		return htmlBody, utils.HTMLToText(htmlBody), nil
+	}
+
+	textBody, err := u.renderTemplate(ctx, name+" text", textTpl, resolved, macros)
+	if err != nil {
+		return "", "", err
+	}
+	return htmlBody, textBody, nil
+}
+
+// render fills the template resolved for locale with the message macros. It returns an
+// empty string when there is no template to resolve.
+func (u *notificationusecase) render(ctx context.Context, name string, templates localized, locale string, macros map[string]string) (string, error) {
+	tpl, resolved := templates.resolve(locale)
+	if tpl == nil {
+		return "", nil
+	}
+	return u.renderTemplate(ctx, name, tpl, resolved, macros)
+}
+
+// renderTemplate fills tpl with the message macros inside a child span of the handler span.
+func (u *notificationusecase) renderTemplate(ctx context.Context, name string, tpl utils.Template, resolved string, macros map[string]string) (string, error) {
+	_, end := u.tracer.StartSpan(ctx, "template.render", map[string]string{
+		"template.name":   name,
+		"template.locale": resolved,
+	})
+
+	content, err := utils.RenderTemplate(tpl, macros)
+	end(err)
+	if err != nil {
+		return "", err
+	}
+	return content, nil
+}
+
+// send runs a channel send of a notification type under the retry policy. The limit of the
+// type is waited for once, it counts notifications. Every attempt then waits for the
+// channel rate limiter, since each attempt counts against the provider quota.
+func (u *notificationusecase) send(ctx context.Context, name string, rateLimiter port.RateLimiter, sendFn func() error) error {
+	if typeRateLimiter := u.typeRateLimiters[name]; typeRateLimiter != nil {
+		if err := typeRateLimiter.WaitUntilAllowed(ctx); err != nil {
+			return fmt.Errorf("rate limit error: %w", err)
+		}
+	}
+
+	return utils.Retry(ctx, u.retryPolicy, func(attempt int) error {
+		if rateLimiter != nil {
+			if err := rateLimiter.WaitUntilAllowed(ctx); err != nil {
+				return fmt.Errorf("rate limit error: %w", err)
+			}
+		}
+
+		err := sendFn()
+		if err != nil && attempt < u.retryPolicy.MaxAttempts && utils.IsTransient(err) {
+			u.logger.Warnw(ctx, "Failed to send "+name+", retrying", "attempt", attempt, "error", err)
+		}
+		return err
+	})
+}
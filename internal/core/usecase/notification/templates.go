@@ -0,0 +1,296 @@
+package notification
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/loganrk/worker-engine/config"
+	"github.com/loganrk/worker-engine/internal/utils"
+)
+
+// Suffixes of the localized template files in a locale directory, e.g. pt-BR.html.
+const (
+	emailSuffix   = ".html"
+	textSuffix    = ".txt"
+	smsSuffix     = ".sms.txt"
+	subjectSuffix = ".subject.txt"
+)
+
+// templateSet is an immutable, validated version of all templates, swapped as a whole on reload.
+type templateSet struct {
+	types   map[string]*typeTemplates // Templates of every registered notification type, keyed by type
+	version string                    // Hash of the template sources
+}
+
+// typeTemplates are the templates of one notification type together with what it was declared with.
+type typeTemplates struct {
+	channel        string        // Channel the type is delivered over
+	requiredMacros []string      // Macros every message of the type must carry
+	inlineCSS      bool          // Whether the CSS of the HTML body is inlined
+	recipientLimit string        // Action taken for a message over the recipient rate limit
+	recipientDelay time.Duration // Longest a message is delayed before it is dead-lettered instead
+	body           localized     // HTML email or SMS templates
+	text           localized     // Plain-text parts of emails, optional
+	subject        localized     // Subject templates of emails, optional
+}
+
+// localized is a template together with its translations, keyed by lowercase locale.
+type localized struct {
+	fallback utils.Template // Default template, nil when there is none
+	locales  map[string]utils.Template
+}
+
+// resolve returns the template of the most specific locale in the fallback chain of locale,
+// or the default template together with an empty locale.
+func (l localized) resolve(locale string) (utils.Template, string) {
+	for _, candidate := range utils.LocaleChain(locale) {
+		if tpl, ok := l.locales[candidate]; ok {
+			return tpl, candidate
+		}
+	}
+	return l.fallback, ""
+}
+
+// exact returns the template of a locale returned by resolve, without falling back to
+// another locale, so that the parts of one email are always in the same language.
+func (l localized) exact(locale string) utils.Template {
+	if locale == "" {
+		return l.fallback
+	}
+	return l.locales[locale]
+}
+
+// templateParser parses the source of a template file.
+type templateParser func(name, src string) (utils.Template, error)
+
+// localeTemplates are the translations found in a locale directory, keyed by lowercase locale.
+type localeTemplates struct {
+	body    map[string]utils.Template
+	text    map[string]utils.Template
+	subject map[string]utils.Template
+}
+
+// loadTemplates reads, parses and validates the templates of every notification type in the registry.
+func loadTemplates(conf config.Notifications) (*templateSet, error) {
+	digest := sha256.New()
+
+	// Read the optional base layout and partials shared by the HTML email templates
+	parseHTML, err := loadLayout(digest, conf.GetLayoutPath(), conf.GetLayoutPartialsDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load layout: %w", err)
+	}
+
+	types := make(map[string]*typeTemplates)
+	for _, typeConf := range conf.GetTypes() {
+		name := typeConf.GetType()
+		if name == "" {
+			return nil, fmt.Errorf("notification type without a name")
+		}
+		if _, ok := types[name]; ok {
+			return nil, fmt.Errorf("notification type %s is declared twice", name)
+		}
+
+		templates, err := loadTypeTemplates(digest, typeConf, parseHTML, conf.GetInlineCSS())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load templates of %s: %w", name, err)
+		}
+		types[name] = templates
+	}
+
+	return &templateSet{
+		types:   types,
+		version: hex.EncodeToString(digest.Sum(nil))[:12],
+	}, nil
+}
+
+// loadTypeTemplates reads and validates the templates of one notification type.
+func loadTypeTemplates(digest hash.Hash, typeConf config.NotificationType, parseHTML templateParser, inlineCSS bool) (*typeTemplates, error) {
+	required := typeConf.GetRequiredMacros()
+
+	// SMS bodies are plain text, email bodies go through the layout
+	parseBody := parseHTML
+	switch typeConf.GetChannel() {
+	case config.CHANNEL_EMAIL:
+	case config.CHANNEL_SMS:
+		parseBody = utils.ParseTextTemplate
+	default:
+		return nil, fmt.Errorf("unknown channel %q", typeConf.GetChannel())
+	}
+
+	// Read and validate the body template from file
+	bodyTpl, err := loadTemplate(digest, typeConf.GetTemplatePath(), parseBody, required)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+
+	// Read and validate the optional plain-text template from file
+	textTpl, err := loadOptionalTemplate(digest, typeConf.GetTextTemplatePath(), utils.ParseTextTemplate, required)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load text template: %w", err)
+	}
+
+	// Read and validate the optional subject template from file
+	subjectTpl, err := loadOptionalTemplate(digest, typeConf.GetSubjectTemplatePath(), utils.ParseTextTemplate, required)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subject template: %w", err)
+	}
+
+	// Read and validate the translations
+	locales, err := loadLocaleDir(digest, typeConf.GetLocaleDir(), typeConf.GetChannel(), parseHTML, required)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load translations: %w", err)
+	}
+
+	return &typeTemplates{
+		channel:        typeConf.GetChannel(),
+		requiredMacros: required,
+		inlineCSS:      typeConf.GetInlineCSS() || inlineCSS,
+		recipientLimit: typeConf.GetRecipientRateLimitAction(),
+		recipientDelay: typeConf.GetRecipientRateLimitMaxDelay(),
+		body:           localized{fallback: bodyTpl, locales: locales.body},
+		text:           localized{fallback: textTpl, locales: locales.text},
+		subject:        localized{fallback: subjectTpl, locales: locales.subject},
+	}, nil
+}
+
+// loadLocaleDir loads the translations of a channel from a locale directory: the <locale>.html,
+// <locale>.txt and <locale>.subject.txt files of an email type, or the <locale>.sms.txt files of
+// an SMS type. Other files are ignored, so email and SMS types can share a directory, and an
+// empty dir yields no translations.
+func loadLocaleDir(digest hash.Hash, dir, channel string, parseHTML templateParser, required []string) (localeTemplates, error) {
+	templates := localeTemplates{
+		body:    make(map[string]utils.Template),
+		text:    make(map[string]utils.Template),
+		subject: make(map[string]utils.Template),
+	}
+	if dir == "" {
+		return templates, nil
+	}
+
+	// Entries are sorted by name, which keeps the version hash stable
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return templates, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		path := filepath.Join(dir, name)
+
+		var err error
+		if channel == config.CHANNEL_SMS {
+			if strings.HasSuffix(name, smsSuffix) {
+				templates.body[localeOf(name, smsSuffix)], err = loadTemplate(digest, path, utils.ParseTextTemplate, required)
+			}
+		} else {
+			switch {
+			case strings.HasSuffix(name, subjectSuffix):
+				templates.subject[localeOf(name, subjectSuffix)], err = loadTemplate(digest, path, utils.ParseTextTemplate, required)
+			case strings.HasSuffix(name, smsSuffix):
+				// Translations of an SMS type sharing the directory
+			case strings.HasSuffix(name, emailSuffix):
+				templates.body[localeOf(name, emailSuffix)], err = loadTemplate(digest, path, parseHTML, required)
+			case strings.HasSuffix(name, textSuffix):
+				templates.text[localeOf(name, textSuffix)], err = loadTemplate(digest, path, utils.ParseTextTemplate, required)
+			}
+		}
+		if err != nil {
+			return templates, err
+		}
+	}
+
+	return templates, nil
+}
+
+// loadLayout reads the base layout and the partials in partialsDir, and returns the parser
+// for HTML email templates. Without a layout, templates are parsed on their own.
+func loadLayout(digest hash.Hash, path, partialsDir string) (templateParser, error) {
+	if path == "" {
+		return utils.ParseHTMLTemplate, nil
+	}
+
+	layoutSrc, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	digest.Write([]byte(filepath.Base(path)))
+	digest.Write(layoutSrc)
+
+	partials := make(map[string]string)
+	if partialsDir != "" {
+		// Entries are sorted by name, which keeps the version hash stable
+		entries, err := os.ReadDir(partialsDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), emailSuffix) {
+				continue
+			}
+
+			src, err := os.ReadFile(filepath.Join(partialsDir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			digest.Write([]byte(entry.Name()))
+			digest.Write(src)
+
+			partials[strings.TrimSuffix(entry.Name(), emailSuffix)] = string(src)
+		}
+	}
+
+	layout, err := utils.ParseLayout(string(layoutSrc), partials)
+	if err != nil {
+		return nil, err
+	}
+	return layout.ParseHTMLTemplate, nil
+}
+
+// localeOf returns the normalized locale a template file name is for.
+func localeOf(name, suffix string) string {
+	chain := utils.LocaleChain(strings.TrimSuffix(name, suffix))
+	if len(chain) == 0 {
+		return ""
+	}
+	return chain[0]
+}
+
+// loadOptionalTemplate loads a template like loadTemplate, or returns nil when no path is configured.
+func loadOptionalTemplate(digest hash.Hash, path string, parse templateParser, required []string) (utils.Template, error) {
+	if path == "" {
+		return nil, nil
+	}
+	return loadTemplate(digest, path, parse, required)
+}
+
+// loadTemplate reads a template file into digest, parses it with the given parser and
+// checks that it renders with only the required macros.
+func loadTemplate(digest hash.Hash, path string, parse templateParser, required []string) (utils.Template, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	digest.Write([]byte(filepath.Base(path)))
+	digest.Write(src)
+
+	tpl, err := parse(filepath.Base(path), string(src))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := utils.ValidateTemplate(tpl, required); err != nil {
+		return nil, fmt.Errorf("template %s uses a macro that is not required: %w", path, err)
+	}
+	return tpl, nil
+}
@@ -0,0 +1,386 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/andybalholm/cascadia"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// cssRule is a style rule whose declarations are copied into the elements it matches.
+type cssRule struct {
+	selector     cascadia.Sel
+	declarations []cssDeclaration
+	order        int // position in the stylesheet, later rules win ties
+}
+
+type cssDeclaration struct {
+	property  string
+	value     string
+	important bool
+}
+
+// matchedDeclaration is a declaration applied to an element, with what decides the cascade.
+type matchedDeclaration struct {
+	cssDeclaration
+	specificity cascadia.Specificity
+	order       int
+}
+
+// InlineCSS copies the rules of the <style> blocks of a rendered HTML email into the
+// style attributes of the elements they match, for clients that strip style blocks.
+// At-rules such as media queries, and rules with pseudo-classes, stay in the head. Media
+// query declarations are marked !important so they still override the inlined styles.
+func InlineCSS(body string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse html: %w", err)
+	}
+
+	// Collect the rules from every style block and keep what cannot be inlined in place
+	var rules []cssRule
+	var styles []*html.Node
+	walkElements(doc, func(n *html.Node) bool {
+		if n.DataAtom == atom.Style {
+			styles = append(styles, n)
+		}
+		return true
+	})
+	for _, style := range styles {
+		var src strings.Builder
+		for child := style.FirstChild; child != nil; child = child.NextSibling {
+			src.WriteString(child.Data)
+		}
+
+		var kept string
+		rules, kept = parseStylesheet(src.String(), rules)
+		if strings.TrimSpace(kept) == "" {
+			style.Parent.RemoveChild(style)
+			continue
+		}
+		for style.FirstChild != nil {
+			style.RemoveChild(style.FirstChild)
+		}
+		style.AppendChild(&html.Node{Type: html.TextNode, Data: kept})
+	}
+
+	walkElements(doc, func(n *html.Node) bool {
+		if n.DataAtom == atom.Head {
+			return false
+		}
+		applyRules(n, rules)
+		return true
+	})
+
+	var buf strings.Builder
+	if err := html.Render(&buf, doc); err != nil {
+		return "", fmt.Errorf("failed to render html: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// walkElements calls fn for every element below n. The children of an element are
+// skipped when fn returns false for it.
+func walkElements(n *html.Node, fn func(*html.Node) bool) {
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		if child.Type == html.ElementNode && !fn(child) {
+			continue
+		}
+		walkElements(child, fn)
+	}
+}
+
+// parseStylesheet appends the inlinable rules of src to rules and returns the CSS that
+// has to stay in the style block.
+func parseStylesheet(src string, rules []cssRule) ([]cssRule, string) {
+	src = stripComments(src)
+
+	var kept strings.Builder
+	for {
+		open := nextDelimiter(src)
+		if open < 0 {
+			break
+		}
+		prelude := strings.TrimSpace(src[:open])
+
+		// Statements such as @import url(x); have no block and stay in the head
+		if src[open] == ';' {
+			if strings.HasPrefix(prelude, "@") {
+				fmt.Fprintf(&kept, "%s;\n", prelude)
+			}
+			src = src[open+1:]
+			continue
+		}
+
+		end := matchingBrace(src, open)
+		if end < 0 {
+			// Unterminated block, leave the rest to the client
+			kept.WriteString(src)
+			break
+		}
+		block := src[open+1 : end]
+		src = src[end+1:]
+
+		if strings.HasPrefix(prelude, "@") {
+			if strings.HasPrefix(prelude, "@media") {
+				block = importantRules(block)
+			}
+			fmt.Fprintf(&kept, "%s {%s}\n", prelude, block)
+			continue
+		}
+
+		declarations := parseDeclarations(block)
+		for _, selector := range strings.Split(prelude, ",") {
+			selector = strings.TrimSpace(selector)
+
+			sel, err := cascadia.Parse(selector)
+			if err != nil || strings.Contains(selector, ":") {
+				// Pseudo-classes such as :hover only apply in the client
+				fmt.Fprintf(&kept, "%s {%s}\n", selector, block)
+				continue
+			}
+			rules = append(rules, cssRule{selector: sel, declarations: declarations, order: len(rules)})
+		}
+	}
+	return rules, kept.String()
+}
+
+// matchingBrace returns the index of the brace closing the one at open, or -1. Braces in
+// quoted strings are skipped.
+func matchingBrace(src string, open int) int {
+	depth := 0
+	for i := open; i < len(src); i++ {
+		switch src[i] {
+		case '"', '\'':
+			i = stringEnd(src, i)
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// nextDelimiter returns the index of the first brace opening a block or semicolon ending a
+// statement outside of parentheses and quoted strings, or -1.
+func nextDelimiter(src string) int {
+	depth := 0
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '"' || c == '\'':
+			i = stringEnd(src, i)
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case (c == '{' || c == ';') && depth == 0:
+			return i
+		}
+	}
+	return -1
+}
+
+// stringEnd returns the index of the quote closing the string opened at start, or the last
+// index of src when it is unterminated.
+func stringEnd(src string, start int) int {
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case src[start]:
+			return i
+		}
+	}
+	return len(src) - 1
+}
+
+// stripComments removes the comments of a stylesheet, leaving quoted strings untouched.
+func stripComments(src string) string {
+	var out strings.Builder
+	for i := 0; i < len(src); i++ {
+		switch {
+		case src[i] == '"' || src[i] == '\'':
+			end := stringEnd(src, i)
+			out.WriteString(src[i : end+1])
+			i = end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				return out.String()
+			}
+			i += end + 3
+		default:
+			out.WriteByte(src[i])
+		}
+	}
+	return out.String()
+}
+
+// importantRules marks every declaration of the rules in a media query block as !important.
+func importantRules(block string) string {
+	var out strings.Builder
+	for {
+		open := nextDelimiter(block)
+		if open < 0 {
+			break
+		}
+		if block[open] == ';' {
+			block = block[open+1:]
+			continue
+		}
+		end := matchingBrace(block, open)
+		if end < 0 {
+			break
+		}
+
+		out.WriteString("\n" + strings.TrimSpace(block[:open]) + " { ")
+		for _, d := range parseDeclarations(block[open+1 : end]) {
+			fmt.Fprintf(&out, "%s: %s !important; ", d.property, d.value)
+		}
+		out.WriteString("}")
+		block = block[end+1:]
+	}
+	return out.String() + "\n"
+}
+
+func parseDeclarations(src string) []cssDeclaration {
+	var declarations []cssDeclaration
+	for _, part := range splitDeclarations(src) {
+		property, value, ok := strings.Cut(part, ":")
+		if !ok {
+			continue
+		}
+		property = strings.ToLower(strings.TrimSpace(property))
+		value = strings.TrimSpace(value)
+
+		important := false
+		if i := strings.LastIndex(strings.ToLower(value), "!important"); i >= 0 {
+			important = true
+			value = strings.TrimSpace(value[:i])
+		}
+
+		if property != "" && value != "" {
+			declarations = append(declarations, cssDeclaration{property: property, value: value, important: important})
+		}
+	}
+	return declarations
+}
+
+// splitDeclarations splits a declaration block on the semicolons outside of parentheses and
+// quoted strings, which keeps values such as url(data:image/png;base64,...) in one piece.
+func splitDeclarations(src string) []string {
+	var parts []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == ';' && depth == 0:
+			parts = append(parts, src[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, src[start:])
+}
+
+// applyRules writes the declarations of the matching rules into the style attribute of n.
+// The existing style attribute wins over the stylesheet, except for !important rules.
+func applyRules(n *html.Node, rules []cssRule) {
+	var matched []matchedDeclaration
+	for _, rule := range rules {
+		if !rule.selector.Match(n) {
+			continue
+		}
+		for _, d := range rule.declarations {
+			matched = append(matched, matchedDeclaration{cssDeclaration: d, specificity: rule.selector.Specificity(), order: rule.order})
+		}
+	}
+	if len(matched) == 0 {
+		return
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if a.specificity != b.specificity {
+			return a.specificity.Less(b.specificity)
+		}
+		return a.order < b.order
+	})
+
+	styleAttr := -1
+	var inline []cssDeclaration
+	for i, attr := range n.Attr {
+		if attr.Key == "style" {
+			styleAttr = i
+			inline = parseDeclarations(attr.Val)
+		}
+	}
+
+	// Cascade order, each later declaration overrides an earlier one of the same property
+	var cascade []cssDeclaration
+	for _, d := range matched {
+		if !d.important {
+			cascade = append(cascade, d.cssDeclaration)
+		}
+	}
+	for _, d := range inline {
+		if !d.important {
+			cascade = append(cascade, d)
+		}
+	}
+	for _, d := range matched {
+		if d.important {
+			cascade = append(cascade, d.cssDeclaration)
+		}
+	}
+	for _, d := range inline {
+		if d.important {
+			cascade = append(cascade, d)
+		}
+	}
+
+	var properties []string
+	values := make(map[string]cssDeclaration, len(cascade))
+	for _, d := range cascade {
+		if _, ok := values[d.property]; !ok {
+			properties = append(properties, d.property)
+		}
+		values[d.property] = d
+	}
+
+	declarations := make([]string, len(properties))
+	for i, property := range properties {
+		d := values[property]
+		if d.important {
+			declarations[i] = d.property + ": " + d.value + " !important"
+		} else {
+			declarations[i] = d.property + ": " + d.value
+		}
+	}
+	style := strings.Join(declarations, "; ")
+
+	if styleAttr >= 0 {
+		n.Attr[styleAttr].Val = style
+	} else {
+		n.Attr = append(n.Attr, html.Attribute{Key: "style", Val: style})
+	}
+}
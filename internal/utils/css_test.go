@@ -0,0 +1,88 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInlineCSS(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		want        []string // fragments of the result
+		wantMissing []string // fragments that must not be in the result
+	}{
+		{
+			name: "type and class rules",
+			body: `<html><head><style>p { color: red; } .note { font-size: 12px; }</style></head><body><p class="note">Hi</p></body></html>`,
+			want: []string{`<p class="note" style="color: red; font-size: 12px">Hi</p>`},
+			// Nothing is left in the style block, so it is removed
+			wantMissing: []string{"<style>"},
+		},
+		{
+			name: "specificity beats order",
+			body: `<html><head><style>#id { color: blue; } p { color: red; }</style></head><body><p id="id">Hi</p></body></html>`,
+			want: []string{`style="color: blue"`},
+		},
+		{
+			name: "existing style attribute wins over the stylesheet",
+			body: `<html><head><style>p { color: red; margin: 0; }</style></head><body><p style="color: green">Hi</p></body></html>`,
+			want: []string{`style="color: green; margin: 0"`},
+		},
+		{
+			name: "important rule wins over the style attribute",
+			body: `<html><head><style>p { color: red !important; }</style></head><body><p style="color: green">Hi</p></body></html>`,
+			want: []string{`style="color: red !important"`},
+		},
+		{
+			name: "media query stays in the head as important",
+			body: `<html><head><style>p { color: red; } @media (max-width: 600px) { p { color: blue; } }</style></head><body><p>Hi</p></body></html>`,
+			want: []string{`@media (max-width: 600px) {`, `p { color: blue !important; }`, `<p style="color: red">Hi</p>`},
+		},
+		{
+			name: "pseudo-class stays in the head",
+			body: `<html><head><style>a:hover { color: red; }</style></head><body><a href="x">Hi</a></body></html>`,
+			want: []string{`a:hover {`, `<a href="x">Hi</a>`},
+		},
+		{
+			name: "semicolon inside url",
+			body: `<html><head><style>div { background: url(data:image/png;base64,AAAA); color: red; }</style></head><body><div>Hi</div></body></html>`,
+			want: []string{`style="background: url(data:image/png;base64,AAAA); color: red"`},
+		},
+		{
+			name: "brace inside a string",
+			body: `<html><head><style>p { font-family: "}"; color: red; } b { color: blue; }</style></head><body><p>Hi</p><b>Bold</b></body></html>`,
+			want: []string{`<p style="font-family: &#34;}&#34;; color: red">Hi</p>`, `<b style="color: blue">Bold</b>`},
+		},
+		{
+			name:        "comment markers inside a string",
+			body:        `<html><head><style>p { font-family: "/*"; color: red; } /* b { color: green; } */ b { color: blue; }</style></head><body><p>Hi</p><b>Bold</b></body></html>`,
+			want:        []string{`color: red"`, `<b style="color: blue">Bold</b>`},
+			wantMissing: []string{"green"},
+		},
+		{
+			name: "bodyless at-rule",
+			body: `<html><head><style>@import url(fonts.css); p { color: red; }</style></head><body><p>Hi</p></body></html>`,
+			want: []string{`@import url(fonts.css);`, `<p style="color: red">Hi</p>`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InlineCSS(tt.body)
+			if err != nil {
+				t.Fatalf("InlineCSS: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("result does not contain %q:\n%s", want, got)
+				}
+			}
+			for _, missing := range tt.wantMissing {
+				if strings.Contains(got, missing) {
+					t.Errorf("result contains %q:\n%s", missing, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTemplateRender is wrapped by every error caused by rendering a notification template.
+var ErrTemplateRender = errors.New("template render failed")
+
+// ErrMissingMacro is wrapped by the error returned for a message that lacks a macro
+// required by its notification type.
+var ErrMissingMacro = errors.New("missing required macros")
+
+// ErrUnknownType is wrapped by the error returned for a message of a type no handler serves.
+var ErrUnknownType = errors.New("unknown message type")
+
+// ErrInvalidMessage is wrapped by the error returned for a consumed record that is not a
+// notification message.
+var ErrInvalidMessage = errors.New("invalid message")
+
+// ErrRateLimited is wrapped by the error returned for a message over the recipient rate
+// limit of its notification type.
+var ErrRateLimited = errors.New("recipient rate limit exceeded")
+
+// ErrDropped is wrapped by the error returned for a message that is discarded on purpose
+// and must not be dead-lettered.
+var ErrDropped = errors.New("notification dropped")
+
+// PermanentError marks a failure that cannot succeed on a later attempt or through
+// another provider, such as a rejected recipient address or an invalid payload.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// Permanent wraps err so that IsTransient reports false for it.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+// IsTransient reports whether err may succeed when tried again. Errors are
+// considered transient unless marked permanent or caused by cancellation.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var permanentErr *PermanentError
+	if errors.As(err, &permanentErr) {
+		return false
+	}
+
+	return !errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// blankLines matches runs of blank lines left by nested block elements.
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// HTMLToText converts a rendered HTML email to readable plain text. Block elements
+// become line breaks, list items are bulleted and links keep their target in
+// parentheses. Head, style and script content is dropped.
+func HTMLToText(body string) string {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var buf strings.Builder
+	writeText(&buf, doc)
+
+	lines := strings.Split(buf.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	text := blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(text) + "\n"
+}
+
+func writeText(buf *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		buf.WriteString(collapseSpace(n.Data))
+		return
+	case html.ElementNode:
+		switch n.DataAtom {
+		case atom.Head, atom.Style, atom.Script, atom.Title:
+			return
+		case atom.Br:
+			buf.WriteString("\n")
+			return
+		case atom.Li:
+			buf.WriteString("\n- ")
+		case atom.A:
+			writeLink(buf, n)
+			return
+		}
+	}
+
+	block := n.Type == html.ElementNode && isBlock(n.DataAtom)
+	if block {
+		buf.WriteString("\n\n")
+	}
+
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		writeText(buf, child)
+	}
+
+	if block {
+		buf.WriteString("\n\n")
+	}
+}
+
+// writeLink writes the link text followed by its target, unless the text already is the target.
+func writeLink(buf *strings.Builder, n *html.Node) {
+	var text strings.Builder
+	for child := n.FirstChild; child != nil; child = child.NextSibling {
+		writeText(&text, child)
+	}
+	label := strings.TrimSpace(text.String())
+
+	var href string
+	for _, attr := range n.Attr {
+		if attr.Key == "href" {
+			href = strings.TrimSpace(attr.Val)
+		}
+	}
+
+	switch {
+	case href == "" || strings.HasPrefix(href, "#") || href == label:
+		buf.WriteString(label)
+	case label == "":
+		buf.WriteString(href)
+	default:
+		buf.WriteString(label + " (" + href + ")")
+	}
+}
+
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Table, atom.Tr, atom.Ul, atom.Ol, atom.Blockquote, atom.Hr, atom.Section,
+		atom.Header, atom.Footer:
+		return true
+	}
+	return false
+}
+
+// collapseSpace replaces every run of whitespace with a single space, as a browser would.
+func collapseSpace(s string) string {
+	if strings.TrimSpace(s) == "" {
+		if s == "" {
+			return ""
+		}
+		return " "
+	}
+
+	collapsed := strings.Join(strings.Fields(s), " ")
+	if strings.TrimLeft(s, " \t\r\n") != s {
+		collapsed = " " + collapsed
+	}
+	if strings.TrimRight(s, " \t\r\n") != s {
+		collapsed += " "
+	}
+	return collapsed
+}
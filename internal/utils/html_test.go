@@ -0,0 +1,55 @@
+package utils
+
+import "testing"
+
+func TestHTMLToText(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "paragraphs",
+			body: `<p>Hello   there</p><p>Second</p>`,
+			want: "Hello there\n\nSecond\n",
+		},
+		{
+			name: "line breaks",
+			body: `<p>One<br>Two</p>`,
+			want: "One\nTwo\n",
+		},
+		{
+			name: "list items",
+			body: `<ul><li>First</li><li>Second</li></ul>`,
+			want: "- First\n- Second\n",
+		},
+		{
+			name: "link with text",
+			body: `<p>Click <a href="https://example.com/reset">here</a> to reset</p>`,
+			want: "Click here (https://example.com/reset) to reset\n",
+		},
+		{
+			name: "link showing its target",
+			body: `<a href="https://example.com">https://example.com</a>`,
+			want: "https://example.com\n",
+		},
+		{
+			name: "anchor link",
+			body: `<a href="#top">Back to top</a>`,
+			want: "Back to top\n",
+		},
+		{
+			name: "head, style and script are dropped",
+			body: `<html><head><title>Title</title><style>p { color: red; }</style></head><body><script>alert(1)</script><p>Body</p></body></html>`,
+			want: "Body\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTMLToText(tt.body); got != tt.want {
+				t.Errorf("HTMLToText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
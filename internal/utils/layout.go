@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"text/template/parse"
+)
+
+// LayoutName is the name HTML email templates reach the base layout under.
+const LayoutName = "layout"
+
+// Layout is a base layout together with the partials shared by HTML email templates.
+type Layout struct {
+	base *htmltemplate.Template
+}
+
+// layoutTemplate executes the base layout with the blocks of a notification template.
+type layoutTemplate struct {
+	set *htmltemplate.Template
+}
+
+func (t layoutTemplate) Execute(w io.Writer, data any) error {
+	return t.set.ExecuteTemplate(w, LayoutName, data)
+}
+
+// templateFuncs are available to layouts, partials and HTML email templates.
+var templateFuncs = htmltemplate.FuncMap{
+	// dict builds the argument of a partial, e.g. {{template "button" (dict "href" .link "label" "Sign in")}}
+	"dict": func(pairs ...any) (map[string]any, error) {
+		if len(pairs)%2 != 0 {
+			return nil, errors.New("dict needs key and value pairs")
+		}
+
+		dict := make(map[string]any, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+			}
+			dict[key] = pairs[i+1]
+		}
+		return dict, nil
+	},
+}
+
+// ParseLayout parses the base layout, which declares the blocks notification templates
+// fill in, and the partials, which are available to both by their map key, e.g.
+// {{template "footer" .}}.
+func ParseLayout(layoutSrc string, partials map[string]string) (*Layout, error) {
+	base, err := htmltemplate.New(LayoutName).Option("missingkey=error").Funcs(templateFuncs).Parse(rewriteLegacyMacros(layoutSrc))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse layout: %w", err)
+	}
+
+	for name, src := range partials {
+		if _, err := base.New(name).Parse(rewriteLegacyMacros(src)); err != nil {
+			return nil, fmt.Errorf("failed to parse partial %s: %w", name, err)
+		}
+	}
+
+	return &Layout{base: base}, nil
+}
+
+// ParseHTMLTemplate parses an email body like the package level ParseHTMLTemplate, with
+// the partials available. A template made only of {{define}} blocks is rendered through
+// the base layout, any other template is rendered on its own.
+func (l *Layout) ParseHTMLTemplate(name, src string) (Template, error) {
+	set, err := l.base.Clone()
+	if err != nil {
+		return nil, err
+	}
+
+	tpl, err := set.New(name).Parse(rewriteLegacyMacros(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	if tpl.Tree == nil || parse.IsEmptyTree(tpl.Tree.Root) {
+		return layoutTemplate{set: set}, nil
+	}
+	return tpl, nil
+}
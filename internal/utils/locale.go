@@ -0,0 +1,22 @@
+package utils
+
+import "strings"
+
+// LocaleChain returns the locales to try for locale, most specific first. "pt_BR" and
+// "pt-BR" both yield ["pt-br", "pt"], an empty locale yields none so that the default
+// applies directly.
+func LocaleChain(locale string) []string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+
+	var chain []string
+	for locale != "" {
+		chain = append(chain, locale)
+
+		i := strings.LastIndex(locale, "-")
+		if i < 0 {
+			break
+		}
+		locale = locale[:i]
+	}
+	return chain
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy describes how often and how far apart a failed operation is retried.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first one
+	BaseDelay   time.Duration // delay before the first retry, doubled on every further retry
+	MaxDelay    time.Duration // upper bound for a single delay, zero means unbounded
+	Jitter      float64       // fraction (0-1) of each delay that is randomised
+}
+
+// RetryError is returned by Retry once the operation has given up.
+type RetryError struct {
+	Attempts int
+	Err      error
+}
+
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("failed after %d attempt(s): %v", e.Attempts, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Retry calls fn until it succeeds, returns a non-transient error, the attempts are
+// exhausted or ctx is done. Attempts are numbered from 1.
+func Retry(ctx context.Context, policy RetryPolicy, fn func(attempt int) error) error {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(attempt)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= maxAttempts || !IsTransient(err) {
+			return &RetryError{Attempts: attempt, Err: err}
+		}
+
+		timer := time.NewTimer(policy.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return &RetryError{Attempts: attempt, Err: fmt.Errorf("%w, last error: %v", ctx.Err(), err)}
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff returns the delay to wait after the given failed attempt.
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if p.MaxDelay > 0 && delay >= p.MaxDelay {
+			break
+		}
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 && delay > 0 {
+		jitter := p.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= time.Duration(rand.Float64() * jitter * float64(delay))
+	}
+
+	return delay
+}
@@ -2,13 +2,102 @@ package utils
 
 import (
 	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"regexp"
 	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
 )
 
-func ReplaceMacros(template string, macros map[string]string) string {
-	for key, value := range macros {
-		placeholder := fmt.Sprintf("{{%s}}", key)
-		template = strings.ReplaceAll(template, placeholder, value)
+// Template is a parsed notification template.
+type Template interface {
+	Execute(w io.Writer, data any) error
+}
+
+// legacyMacro matches the {{name}} placeholders of templates written for plain string
+// replacement, which Go templates would otherwise treat as function calls.
+var legacyMacro = regexp.MustCompile(`{{\s*([A-Za-z_][A-Za-z0-9_]*)\s*}}`)
+
+// templateKeywords are bare actions that must not be rewritten to a macro lookup.
+var templateKeywords = map[string]bool{
+	"end": true, "else": true, "break": true, "continue": true,
+	"nil": true, "true": true, "false": true,
+}
+
+// ParseHTMLTemplate parses an email body with html/template, so macro values are escaped
+// for the context they appear in. Placeholders can be written {{.name}} or {{name}}.
+func ParseHTMLTemplate(name, src string) (Template, error) {
+	tpl, err := htmltemplate.New(name).Option("missingkey=error").Funcs(templateFuncs).Parse(rewriteLegacyMacros(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+
+	// Without a layout, a template made only of {{define}} blocks would render nothing
+	if tpl.Tree == nil || parse.IsEmptyTree(tpl.Tree.Root) {
+		return nil, fmt.Errorf("template %s only defines blocks and needs a layout", name)
+	}
+	return tpl, nil
+}
+
+// ParseTextTemplate parses a plain text template, such as an SMS body, with text/template.
+// Placeholders can be written {{.name}} or {{name}}.
+func ParseTextTemplate(name, src string) (Template, error) {
+	tpl, err := texttemplate.New(name).Option("missingkey=error").Parse(rewriteLegacyMacros(src))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+	}
+	return tpl, nil
+}
+
+// RenderTemplate executes tpl with the message macros. Referencing a macro that is not
+// supplied fails the render, the returned error wraps ErrTemplateRender.
+func RenderTemplate(tpl Template, macros map[string]string) (string, error) {
+	if macros == nil {
+		macros = map[string]string{}
 	}
-	return template
+
+	var buf strings.Builder
+	if err := tpl.Execute(&buf, macros); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTemplateRender, err)
+	}
+	return buf.String(), nil
+}
+
+// CheckMacros returns a permanent error listing the required macros that are absent or
+// empty in macros, or nil when all of them are supplied.
+func CheckMacros(macros map[string]string, required []string) error {
+	var missing []string
+	for _, name := range required {
+		if macros[name] == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return Permanent(fmt.Errorf("%w: %s", ErrMissingMacro, strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+// ValidateTemplate renders tpl with only the required macros, so that a template
+// referencing a macro its notification type does not require fails at startup instead
+// of on every message.
+func ValidateTemplate(tpl Template, required []string) error {
+	macros := make(map[string]string, len(required))
+	for _, name := range required {
+		macros[name] = name
+	}
+
+	_, err := RenderTemplate(tpl, macros)
+	return err
+}
+
+func rewriteLegacyMacros(src string) string {
+	return legacyMacro.ReplaceAllStringFunc(src, func(action string) string {
+		name := legacyMacro.FindStringSubmatch(action)[1]
+		if templateKeywords[name] {
+			return action
+		}
+		return "{{." + name + "}}"
+	})
 }